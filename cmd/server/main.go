@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,8 +16,14 @@ import (
 
 	"qwen3-compatibility/internal/config"
 	"qwen3-compatibility/internal/handlers"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/metrics"
 	"qwen3-compatibility/internal/middleware"
 	"qwen3-compatibility/internal/services"
+	"qwen3-compatibility/internal/storage"
+	"qwen3-compatibility/internal/store"
+	"qwen3-compatibility/internal/tracing"
+	"qwen3-compatibility/internal/transcode"
 	"qwen3-compatibility/pkg/client"
 )
 
@@ -38,7 +44,14 @@ var rootCmd = &cobra.Command{
 	Long: `A Go implementation providing OpenAI-compatible APIs for Qwen3 services.
 
 Available Endpoints:
-  POST /v1/audio/transcriptions  - Audio transcription using Qwen3 ASR models`,
+  POST /v1/audio/transcriptions  - Audio transcription using Qwen3 ASR models
+  /v1/audio/transcriptions/jobs  - Asynchronous transcription jobs
+  /v1/files                      - OpenAI Files API
+  /v1/batches                    - OpenAI Batch API
+  /admin/keys                    - Virtual API key management (requires admin token)
+  /admin/vocabularies            - Custom vocabulary management (requires admin token)
+  /admin/webhooks/dead-letters   - Undelivered job callbacks (requires admin token)
+  GET  /metrics                  - Prometheus metrics, on metrics.address`,
 	RunE: runServer,
 }
 
@@ -49,7 +62,14 @@ var serverCmd = &cobra.Command{
 	Long: `Start the HTTP server providing OpenAI-compatible APIs for Qwen3 services.
 
 Available Endpoints:
-  POST /v1/audio/transcriptions  - Audio transcription using Qwen3 ASR models`,
+  POST /v1/audio/transcriptions  - Audio transcription using Qwen3 ASR models
+  /v1/audio/transcriptions/jobs  - Asynchronous transcription jobs
+  /v1/files                      - OpenAI Files API
+  /v1/batches                    - OpenAI Batch API
+  /admin/keys                    - Virtual API key management (requires admin token)
+  /admin/vocabularies            - Custom vocabulary management (requires admin token)
+  /admin/webhooks/dead-letters   - Undelivered job callbacks (requires admin token)
+  GET  /metrics                  - Prometheus metrics, on metrics.address`,
 	RunE: runServer,
 }
 
@@ -77,7 +97,8 @@ func init() {
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatalf("Error executing command: %v", err)
+		slog.Error("Error executing command", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -98,6 +119,11 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 	cfg = loadedCfg
 
+	// Setup structured logging
+	if _, err := logging.Setup(&cfg.Log); err != nil {
+		return fmt.Errorf("failed to setup logging: %w", err)
+	}
+
 	// Set gin mode based on environment variable, default to release
 	// This follows 12-factor app principles and provides a secure default
 	// Development: export GIN_MODE=debug
@@ -108,36 +134,202 @@ func runServer(cmd *cobra.Command, args []string) error {
 	}
 	gin.SetMode(ginMode)
 
+	// Name the models recorded in metrics before any workers start
+	metrics.SetModels(cfg.MetricModels())
+
+	// Setup tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, Version)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
 	// Create services and clients
 	dashscopeClient := client.NewDashScopeClient(
 		cfg.DashScope.Timeout,
 	)
 
-	uploadService := services.NewUploadService(dashscopeClient, &cfg.Upload)
-	asrService := services.NewASRService(dashscopeClient)
+	// Uploads are converted with ffmpeg when transcoding is enabled and ffmpeg is installed
+	var transcoder *transcode.Transcoder
+	if cfg.Transcode.Enabled {
+		transcoder, err = transcode.New(&cfg.Transcode)
+		if err != nil {
+			slog.Warn("Transcoding disabled", "error", err)
+		}
+	}
+
+	uploadService := services.NewUploadService(dashscopeClient, &cfg.Upload, &cfg.VAD, &cfg.Chunking, transcoder)
+	// Models are routed to DashScope or to configured OpenAI-compatible servers
+	providers, err := services.NewProviderRegistry(&cfg.Providers, dashscopeClient, cfg.DashScope.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed to create ASR providers: %w", err)
+	}
+	slog.Info("ASR providers configured", "providers", providers.Names(), "routed_models", len(cfg.Providers.Models))
+	asrService := services.NewASRService(providers, cfg.DashScope.EnableITN)
+
+	// Long WAV and PCM recordings are split into chunks that fit the ASR request limits
+	var chunkingService services.IChunkingService
+	if cfg.Chunking.Enabled {
+		chunkingService = services.NewChunkingService(uploadService, asrService, &cfg.Chunking)
+	}
+
+	// Diarized requests are transcribed by a Paraformer file transcription task
+	var diarizationService services.IDiarizationService
+	if cfg.Diarization.Enabled {
+		taskClient := client.NewTaskClient(cfg.Diarization.BaseURL, cfg.DashScope.Timeout, time.Duration(cfg.Diarization.PollIntervalMs)*time.Millisecond)
+		diarizationService = services.NewDiarizationService(taskClient, &cfg.Diarization)
+	}
+
+	// Open virtual key store
+	var keyStore *store.KeyStore
+	if cfg.Keys.StorePath != "" {
+		keyStore, err = store.NewKeyStore(cfg.Keys.StorePath)
+		if err != nil {
+			return fmt.Errorf("failed to open key store: %w", err)
+		}
+	}
+
+	// Open vocabulary store. Vocabularies defined in the configuration are available
+	// without it.
+	var vocabularyStore *store.VocabularyStore
+	if cfg.Vocabulary.StorePath != "" {
+		vocabularyStore, err = store.NewVocabularyStore(cfg.Vocabulary.StorePath)
+		if err != nil {
+			return fmt.Errorf("failed to open vocabulary store: %w", err)
+		}
+	}
+	vocabularyService := services.NewVocabularyService(vocabularyStore, &cfg.Vocabulary)
+	postProcessService := services.NewPostProcessService(&cfg.Replacements, &cfg.Redaction)
+
+	// Start asynchronous job workers and webhook delivery
+	var jobService *services.JobService
+	var webhookService *services.WebhookService
+	if cfg.Jobs.Enabled {
+		jobStore, err := store.NewJobStore(cfg.Jobs.Dir)
+		if err != nil {
+			return fmt.Errorf("failed to open job store: %w", err)
+		}
+
+		if cfg.Webhooks.Secret != "" {
+			deadLetters, err := store.NewDeadLetterStore(cfg.Webhooks.DeadLetterPath)
+			if err != nil {
+				return fmt.Errorf("failed to open webhook dead-letter store: %w", err)
+			}
+			webhookService = services.NewWebhookService(jobStore, deadLetters, &cfg.Webhooks)
+		} else {
+			slog.Info("Job callbacks disabled: webhooks.secret is not set")
+		}
+
+		jobService = services.NewJobService(jobStore, keyStore, uploadService, asrService, chunkingService, diarizationService, postProcessService, webhookService, &cfg.Jobs)
+		jobService.Start()
+	}
+
+	// Open the file store. Stored files can be transcribed by file_id and are the input
+	// and output of batches.
+	var fileStore *store.FileStore
+	var fileService services.IFileService
+	if cfg.Files.Enabled {
+		contents, err := storage.New(&cfg.Files)
+		if err != nil {
+			return fmt.Errorf("failed to open file storage: %w", err)
+		}
+		fileStore, err = store.NewFileStore(cfg.Files.Dir, contents)
+		if err != nil {
+			return fmt.Errorf("failed to open file store: %w", err)
+		}
+		fileService = services.NewFileService(fileStore, uploadService)
+	}
 
 	// Create handlers
-	transcriptionHandler := handlers.NewTranscriptionHandler(uploadService, asrService, cfg)
+	transcriptionHandler := handlers.NewTranscriptionHandler(uploadService, fileService, asrService, chunkingService, diarizationService, vocabularyService, postProcessService, cfg)
+
+	var jobsHandler *handlers.JobsHandler
+	if jobService != nil {
+		var diarizationModel string
+		if diarizationService != nil {
+			diarizationModel = diarizationService.Model()
+		}
+		jobsHandler = handlers.NewJobsHandler(uploadService, fileService, jobService, vocabularyService, postProcessService, webhookService != nil, diarizationModel)
+	}
+
+	var webhooksHandler *handlers.WebhooksHandler
+	if webhookService != nil {
+		webhooksHandler = handlers.NewWebhooksHandler(webhookService)
+	}
+
+	// Start batch workers. Batch lines are served by an internal router that is not
+	// exposed over the network.
+	var filesHandler *handlers.FilesHandler
+	var batchesHandler *handlers.BatchesHandler
+	var batchService *services.BatchService
+	if fileService != nil {
+		filesHandler = handlers.NewFilesHandler(fileService, cfg.Files.MaxFileSize)
+
+		if cfg.Batches.Enabled {
+			batchStore, err := store.NewBatchStore(cfg.Batches.Dir)
+			if err != nil {
+				return fmt.Errorf("failed to open batch store: %w", err)
+			}
+			internalRouter := setupInternalRouter(transcriptionHandler, keyStore)
+			batchService = services.NewBatchService(batchStore, fileStore, internalRouter, &cfg.Batches)
+			batchService.Start()
+			batchesHandler = handlers.NewBatchesHandler(batchService)
+		}
+	}
+
+	var adminHandler *handlers.AdminHandler
+	if cfg.Admin.Token != "" && keyStore != nil {
+		adminHandler = handlers.NewAdminHandler(keyStore, postProcessService)
+	} else {
+		slog.Info("Admin API disabled: admin.token and keys.store_path must both be set")
+	}
+	vocabulariesHandler := handlers.NewVocabulariesHandler(vocabularyService)
 
 	// Setup router
-	router := setupRouter(transcriptionHandler)
+	router := setupRouter(transcriptionHandler, jobsHandler, filesHandler, batchesHandler, adminHandler, vocabulariesHandler, webhooksHandler, keyStore)
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         cfg.GetServerAddress(),
 		Handler:      router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
+	}
+
+	// Serve metrics on their own address, so that they are not exposed with the API
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.Path, metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:              cfg.Metrics.Address,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			slog.Info("Starting metrics server", "address", cfg.Metrics.Address, "path", cfg.Metrics.Path)
+
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Metrics server failed to start", "error", err)
+				os.Exit(1)
+			}
+		}()
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting server on %s", cfg.GetServerAddress())
-		log.Printf("Max file size: %d bytes", cfg.Upload.MaxFileSize)
+		slog.Info("Starting server", "address", cfg.GetServerAddress(), "max_file_size", cfg.Upload.MaxFileSize)
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
+			slog.Error("Server failed to start", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -146,7 +338,7 @@ func runServer(cmd *cobra.Command, args []string) error {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
 
 	// Create a deadline for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -154,18 +346,46 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 	// Attempt graceful shutdown
 	if err := server.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
+		slog.Error("Server forced to shutdown", "error", err)
 		return err
 	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			slog.Error("Metrics server forced to shutdown", "error", err)
+		}
+	}
 
-	log.Println("Server shutdown complete")
+	// Stop job workers; interrupted jobs resume on the next start
+	if jobService != nil {
+		if err := jobService.Stop(ctx); err != nil {
+			slog.Error("Job workers did not stop in time", "error", err)
+		}
+	}
+	if batchService != nil {
+		if err := batchService.Stop(ctx); err != nil {
+			slog.Error("Batch workers did not stop in time", "error", err)
+		}
+	}
+	if webhookService != nil {
+		if err := webhookService.Stop(ctx); err != nil {
+			slog.Error("Webhook deliveries did not stop in time", "error", err)
+		}
+	}
+
+	slog.Info("Server shutdown complete")
 	return nil
 }
 
-func setupRouter(transcriptionHandler *handlers.TranscriptionHandler) *gin.Engine {
+func setupRouter(transcriptionHandler *handlers.TranscriptionHandler, jobsHandler *handlers.JobsHandler, filesHandler *handlers.FilesHandler, batchesHandler *handlers.BatchesHandler, adminHandler *handlers.AdminHandler, vocabulariesHandler *handlers.VocabulariesHandler, webhooksHandler *handlers.WebhooksHandler, keyStore *store.KeyStore) *gin.Engine {
 	router := gin.New()
 
 	// Add middleware
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Tracing())
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.Metrics())
+	}
+	router.Use(middleware.RequestLogger())
 	router.Use(middleware.Logger())
 	router.Use(middleware.Recovery())
 	router.Use(middleware.ErrorHandler())
@@ -173,10 +393,77 @@ func setupRouter(transcriptionHandler *handlers.TranscriptionHandler) *gin.Engin
 
 	// Setup routes
 	api := router.Group("/v1")
-	api.Use(middleware.AuthMiddleware()) // Add auth middleware to API routes
+	api.Use(middleware.AuthMiddleware(keyStore, cfg.Keys.RequireVirtualKey)) // Add auth middleware to API routes
 	{
 		api.POST("/audio/transcriptions", transcriptionHandler.Transcription)
+
+		if jobsHandler != nil {
+			api.POST("/audio/transcriptions/jobs", jobsHandler.CreateJob)
+			api.GET("/audio/transcriptions/jobs/:id", jobsHandler.GetJob)
+			api.DELETE("/audio/transcriptions/jobs/:id", jobsHandler.CancelJob)
+		}
+
+		if filesHandler != nil {
+			api.POST("/files", filesHandler.UploadFile)
+			api.GET("/files", filesHandler.ListFiles)
+			api.GET("/files/:id", filesHandler.GetFile)
+			api.GET("/files/:id/content", filesHandler.GetFileContent)
+			api.DELETE("/files/:id", filesHandler.DeleteFile)
+		}
+
+		if batchesHandler != nil {
+			api.POST("/batches", batchesHandler.CreateBatch)
+			api.GET("/batches", batchesHandler.ListBatches)
+			api.GET("/batches/:id", batchesHandler.GetBatch)
+			api.POST("/batches/:id/cancel", batchesHandler.CancelBatch)
+		}
+	}
+
+	// Admin routes are only registered when an admin token is configured
+	if cfg.Admin.Token != "" {
+		admin := router.Group("/admin")
+		admin.Use(middleware.AdminAuth(cfg.Admin.Token))
+
+		if adminHandler != nil {
+			admin.POST("/keys", adminHandler.CreateKey)
+			admin.GET("/keys", adminHandler.ListKeys)
+			admin.GET("/keys/:id", adminHandler.GetKey)
+			admin.PATCH("/keys/:id", adminHandler.UpdateKey)
+			admin.POST("/keys/:id/rotate", adminHandler.RotateKey)
+			admin.POST("/keys/:id/disable", adminHandler.DisableKey)
+			admin.POST("/keys/:id/enable", adminHandler.EnableKey)
+			admin.DELETE("/keys/:id", adminHandler.DeleteKey)
+		}
+
+		admin.GET("/vocabularies", vocabulariesHandler.ListVocabularies)
+		admin.GET("/vocabularies/:id", vocabulariesHandler.GetVocabulary)
+		admin.PUT("/vocabularies/:id", vocabulariesHandler.PutVocabulary)
+		admin.DELETE("/vocabularies/:id", vocabulariesHandler.DeleteVocabulary)
+
+		if webhooksHandler != nil {
+			admin.GET("/webhooks/dead-letters", webhooksHandler.ListDeadLetters)
+			admin.POST("/webhooks/dead-letters/:id/redeliver", webhooksHandler.Redeliver)
+		}
 	}
 
 	return router
 }
+
+// setupInternalRouter builds the router that serves batch lines in-process. Requests are
+// authenticated with the batch creator's key carried in the request context.
+func setupInternalRouter(transcriptionHandler *handlers.TranscriptionHandler, keyStore *store.KeyStore) *gin.Engine {
+	router := gin.New()
+
+	router.Use(middleware.RequestID())
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.Metrics())
+	}
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Logger())
+	router.Use(middleware.Recovery())
+	router.Use(middleware.InternalAuth(keyStore))
+
+	router.POST("/v1/audio/transcriptions", transcriptionHandler.Transcription)
+
+	return router
+}
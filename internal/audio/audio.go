@@ -0,0 +1,318 @@
+// Package audio reads uncompressed PCM audio from WAV containers and raw PCM streams
+package audio
+
+import (
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+var ErrUnsupportedFormat = stderrors.New("unsupported audio format")
+
+// Encoding is the sample encoding of PCM audio, using the WAVE format tags
+type Encoding uint16
+
+const (
+	EncodingPCM   Encoding = 1
+	EncodingFloat Encoding = 3
+)
+
+// Format describes interleaved PCM frames
+type Format struct {
+	Encoding      Encoding
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+}
+
+// FrameSize is the number of bytes of one frame, one sample for every channel
+func (f Format) FrameSize() int {
+	return f.Channels * f.BitsPerSample / 8
+}
+
+// ByteRate is the number of bytes of one second of audio
+func (f Format) ByteRate() int {
+	return f.SampleRate * f.FrameSize()
+}
+
+func (f Format) validate() error {
+	if f.Channels < 1 || f.SampleRate < 1 {
+		return fmt.Errorf("%w: %d channels at %d Hz", ErrUnsupportedFormat, f.Channels, f.SampleRate)
+	}
+	switch {
+	case f.Encoding == EncodingPCM && (f.BitsPerSample == 8 || f.BitsPerSample == 16 || f.BitsPerSample == 24 || f.BitsPerSample == 32):
+	case f.Encoding == EncodingFloat && (f.BitsPerSample == 32 || f.BitsPerSample == 64):
+	default:
+		return fmt.Errorf("%w: encoding %d with %d bits per sample", ErrUnsupportedFormat, f.Encoding, f.BitsPerSample)
+	}
+	return nil
+}
+
+// Clip is a span of PCM frames read on demand from an io.ReaderAt, so long recordings
+// are never loaded into memory at once
+type Clip struct {
+	Format Format
+	data   *io.SectionReader
+}
+
+// NewClip returns the clip of size bytes of PCM frames stored at r. A trailing partial
+// frame is ignored.
+func NewClip(r io.ReaderAt, size int64, format Format) (*Clip, error) {
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+	size -= size % int64(format.FrameSize())
+	return &Clip{Format: format, data: io.NewSectionReader(r, 0, size)}, nil
+}
+
+// Frames returns the number of frames in the clip
+func (c *Clip) Frames() int64 {
+	return c.data.Size() / int64(c.Format.FrameSize())
+}
+
+// Duration returns the length of the clip
+func (c *Clip) Duration() time.Duration {
+	return c.FrameOffset(c.Frames())
+}
+
+// FrameOffset converts a frame index to a time offset
+func (c *Clip) FrameOffset(frame int64) time.Duration {
+	return time.Duration(frame) * time.Second / time.Duration(c.Format.SampleRate)
+}
+
+// FrameAt converts a time offset to a frame index, clamped to the clip
+func (c *Clip) FrameAt(offset time.Duration) int64 {
+	frame := int64(offset.Seconds() * float64(c.Format.SampleRate))
+	return max(0, min(frame, c.Frames()))
+}
+
+// Slice returns the frames [start, end) of the clip
+func (c *Clip) Slice(start, end int64) *Clip {
+	start = max(0, min(start, c.Frames()))
+	end = max(start, min(end, c.Frames()))
+	frameSize := int64(c.Format.FrameSize())
+	return &Clip{
+		Format: c.Format,
+		data:   io.NewSectionReader(c.data, start*frameSize, (end-start)*frameSize),
+	}
+}
+
+// Channel returns channel ch of the clip, counted from 0, as a mono clip
+func (c *Clip) Channel(ch int) *Clip {
+	sampleSize := c.Format.BitsPerSample / 8
+	format := c.Format
+	format.Channels = 1
+	reader := channelReaderAt{
+		data:       c.data,
+		frameSize:  c.Format.FrameSize(),
+		sampleSize: sampleSize,
+		offset:     ch * sampleSize,
+	}
+	return &Clip{
+		Format: format,
+		data:   io.NewSectionReader(reader, 0, c.Frames()*int64(sampleSize)),
+	}
+}
+
+// WindowStats describes one analysis window of a clip with all channels mixed down
+type WindowStats struct {
+	// RMS is the root mean square level, between 0 and 1
+	RMS float64
+	// ZeroCrossingRate is the fraction of consecutive samples that change sign
+	ZeroCrossingRate float64
+}
+
+// Analyze returns the statistics of consecutive windows of the clip. The last window may
+// be shorter than the others.
+func (c *Clip) Analyze(window time.Duration) ([]WindowStats, error) {
+	windowFrames := max(1, int(window.Seconds()*float64(c.Format.SampleRate)))
+	frameSize := c.Format.FrameSize()
+	buf := make([]byte, windowFrames*frameSize)
+
+	stats := make([]WindowStats, 0, c.Frames()/int64(windowFrames)+1)
+	reader := io.NewSectionReader(c.data, 0, c.data.Size())
+	for {
+		n, err := io.ReadFull(reader, buf)
+		frames := n / frameSize
+		if frames > 0 {
+			var sum float64
+			var crossings int
+			var previous float64
+			for i := 0; i < frames; i++ {
+				sample := c.mixFrame(buf[i*frameSize : (i+1)*frameSize])
+				sum += sample * sample
+				if i > 0 && (sample >= 0) != (previous >= 0) {
+					crossings++
+				}
+				previous = sample
+			}
+			stats = append(stats, WindowStats{
+				RMS:              math.Sqrt(sum / float64(frames)),
+				ZeroCrossingRate: float64(crossings) / float64(frames),
+			})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return stats, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Levels returns the RMS level, between 0 and 1, of consecutive windows of the clip with
+// all channels mixed down. The last window may be shorter than the others.
+func (c *Clip) Levels(window time.Duration) ([]float64, error) {
+	stats, err := c.Analyze(window)
+	if err != nil {
+		return nil, err
+	}
+	levels := make([]float64, len(stats))
+	for i, window := range stats {
+		levels[i] = window.RMS
+	}
+	return levels, nil
+}
+
+// Join returns the clips played one after another. All clips must have the same format.
+func Join(clips ...*Clip) (*Clip, error) {
+	if len(clips) == 0 {
+		return nil, fmt.Errorf("%w: no clips to join", ErrUnsupportedFormat)
+	}
+
+	parts := make([]*io.SectionReader, len(clips))
+	var size int64
+	for i, clip := range clips {
+		if clip.Format != clips[0].Format {
+			return nil, fmt.Errorf("%w: cannot join clips of different formats", ErrUnsupportedFormat)
+		}
+		parts[i] = clip.data
+		size += clip.data.Size()
+	}
+
+	return &Clip{
+		Format: clips[0].Format,
+		data:   io.NewSectionReader(multiReaderAt(parts), 0, size),
+	}, nil
+}
+
+// WAV returns the clip as a WAV file. The returned reader supports Read, ReadAt and Seek.
+func (c *Clip) WAV() *io.SectionReader {
+	header := wavHeader(c.Format, c.data.Size())
+	return io.NewSectionReader(concatReaderAt{header: header, data: c.data}, 0, int64(len(header))+c.data.Size())
+}
+
+// mixFrame returns the average of the samples of one frame, scaled to [-1, 1]
+func (c *Clip) mixFrame(frame []byte) float64 {
+	bytesPerSample := c.Format.BitsPerSample / 8
+	var sum float64
+	for ch := 0; ch < c.Format.Channels; ch++ {
+		sum += decodeSample(frame[ch*bytesPerSample:(ch+1)*bytesPerSample], c.Format)
+	}
+	return sum / float64(c.Format.Channels)
+}
+
+// decodeSample converts one little-endian sample to [-1, 1]
+func decodeSample(b []byte, format Format) float64 {
+	if format.Encoding == EncodingFloat {
+		if format.BitsPerSample == 64 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+
+	switch format.BitsPerSample {
+	case 8:
+		// 8-bit WAV samples are unsigned
+		return (float64(b[0]) - 128) / 128
+	case 16:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+	case 24:
+		v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+		return float64(v) / (1 << 23)
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+	}
+}
+
+// channelReaderAt serves the samples of one channel of interleaved frames
+type channelReaderAt struct {
+	data       io.ReaderAt
+	frameSize  int
+	sampleSize int
+	// offset of the channel's sample within a frame
+	offset int
+}
+
+func (r channelReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	sampleSize := int64(r.sampleSize)
+	first := off / sampleSize
+	last := (off + int64(len(p)) + sampleSize - 1) / sampleSize
+
+	frames := make([]byte, (last-first)*int64(r.frameSize))
+	n, err := r.data.ReadAt(frames, first*int64(r.frameSize))
+	complete := n / r.frameSize
+	samples := make([]byte, complete*r.sampleSize)
+	for i := 0; i < complete; i++ {
+		frame := frames[i*r.frameSize+r.offset:]
+		copy(samples[i*r.sampleSize:(i+1)*r.sampleSize], frame[:r.sampleSize])
+	}
+
+	skip := int(off - first*sampleSize)
+	if skip > len(samples) {
+		skip = len(samples)
+	}
+	m := copy(p, samples[skip:])
+	if m < len(p) {
+		if err == nil {
+			err = io.EOF
+		}
+		return m, err
+	}
+	return m, nil
+}
+
+// multiReaderAt serves sections one after another as one io.ReaderAt
+type multiReaderAt []*io.SectionReader
+
+func (r multiReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	var n int
+	for _, part := range r {
+		if off >= part.Size() {
+			off -= part.Size()
+			continue
+		}
+		m, err := part.ReadAt(p[n:], off)
+		n += m
+		if n == len(p) {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+		off = 0
+	}
+	return n, io.EOF
+}
+
+// concatReaderAt serves a header followed by data as one io.ReaderAt
+type concatReaderAt struct {
+	header []byte
+	data   io.ReaderAt
+}
+
+func (r concatReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	var n int
+	if off < int64(len(r.header)) {
+		n = copy(p, r.header[off:])
+		if n == len(p) {
+			return n, nil
+		}
+		off = int64(len(r.header))
+	}
+	m, err := r.data.ReadAt(p[n:], off-int64(len(r.header)))
+	return n + m, err
+}
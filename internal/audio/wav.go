@@ -0,0 +1,104 @@
+package audio
+
+import (
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"io"
+)
+
+var ErrNotWAV = stderrors.New("not a RIFF/WAVE file")
+
+// WAVHeaderSize is the size of the header written by Clip.WAV
+const WAVHeaderSize = 44
+
+// Format tag of WAVE_FORMAT_EXTENSIBLE; the actual encoding is in the sub-format GUID
+const wavFormatExtensible = 0xFFFE
+
+// OpenWAV returns the PCM frames of a WAV file of size bytes stored at r. Only the headers
+// are read. Streamed WAV files with a missing or oversized data length are read to the end
+// of the file.
+func OpenWAV(r io.ReaderAt, size int64) (*Clip, error) {
+	var riff [12]byte
+	if _, err := r.ReadAt(riff[:], 0); err != nil {
+		return nil, ErrNotWAV
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	var format *Format
+	offset := int64(len(riff))
+	for offset+8 <= size {
+		var chunk [8]byte
+		if _, err := r.ReadAt(chunk[:], offset); err != nil {
+			return nil, fmt.Errorf("failed to read WAV chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			parsed, err := readWAVFormat(r, body, chunkSize)
+			if err != nil {
+				return nil, err
+			}
+			format = parsed
+		case "data":
+			if format == nil {
+				return nil, fmt.Errorf("%w: data chunk before fmt chunk", ErrNotWAV)
+			}
+			if chunkSize == 0xFFFFFFFF || body+chunkSize > size {
+				chunkSize = size - body
+			}
+			return NewClip(io.NewSectionReader(r, body, chunkSize), chunkSize, *format)
+		}
+
+		// Chunks are padded to an even size
+		offset = body + chunkSize + chunkSize%2
+	}
+
+	return nil, fmt.Errorf("%w: no data chunk", ErrNotWAV)
+}
+
+func readWAVFormat(r io.ReaderAt, offset, size int64) (*Format, error) {
+	if size < 16 {
+		return nil, fmt.Errorf("%w: short fmt chunk", ErrNotWAV)
+	}
+	buf := make([]byte, min(size, 40))
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return nil, fmt.Errorf("failed to read WAV format: %w", err)
+	}
+
+	tag := binary.LittleEndian.Uint16(buf[0:2])
+	if tag == wavFormatExtensible && len(buf) >= 26 {
+		tag = binary.LittleEndian.Uint16(buf[24:26])
+	}
+
+	return &Format{
+		Encoding:      Encoding(tag),
+		Channels:      int(binary.LittleEndian.Uint16(buf[2:4])),
+		SampleRate:    int(binary.LittleEndian.Uint32(buf[4:8])),
+		BitsPerSample: int(binary.LittleEndian.Uint16(buf[14:16])),
+	}, nil
+}
+
+// wavHeader returns a canonical 44-byte WAV header for dataSize bytes of frames
+func wavHeader(format Format, dataSize int64) []byte {
+	header := make([]byte, WAVHeaderSize)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(WAVHeaderSize-8+dataSize))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], uint16(format.Encoding))
+	binary.LittleEndian.PutUint16(header[22:24], uint16(format.Channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(format.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(format.ByteRate()))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(format.FrameSize()))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(format.BitsPerSample))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(dataSize))
+	return header
+}
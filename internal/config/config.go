@@ -2,29 +2,326 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	DashScope DashScopeConfig `mapstructure:"dashscope"`
-	Upload    UploadConfig    `mapstructure:"upload"`
+	Server       ServerConfig       `mapstructure:"server"`
+	DashScope    DashScopeConfig    `mapstructure:"dashscope"`
+	Upload       UploadConfig       `mapstructure:"upload"`
+	Admin        AdminConfig        `mapstructure:"admin"`
+	Keys         KeysConfig         `mapstructure:"keys"`
+	Metrics      MetricsConfig      `mapstructure:"metrics"`
+	Tracing      TracingConfig      `mapstructure:"tracing"`
+	Log          LogConfig          `mapstructure:"log"`
+	Jobs         JobsConfig         `mapstructure:"jobs"`
+	Webhooks     WebhooksConfig     `mapstructure:"webhooks"`
+	Files        FilesConfig        `mapstructure:"files"`
+	Batches      BatchesConfig      `mapstructure:"batches"`
+	Chunking     ChunkingConfig     `mapstructure:"chunking"`
+	VAD          VADConfig          `mapstructure:"vad"`
+	Transcode    TranscodeConfig    `mapstructure:"transcode"`
+	Diarization  DiarizationConfig  `mapstructure:"diarization"`
+	Providers    ProvidersConfig    `mapstructure:"providers"`
+	Vocabulary   VocabularyConfig   `mapstructure:"vocabulary"`
+	Replacements ReplacementsConfig `mapstructure:"replacements"`
+	Redaction    RedactionConfig    `mapstructure:"redaction"`
 }
 
 type ServerConfig struct {
 	Port string `mapstructure:"port"`
 	Host string `mapstructure:"host"`
+	// Timeouts in seconds
+	ReadTimeout  int `mapstructure:"read_timeout"`
+	WriteTimeout int `mapstructure:"write_timeout"`
+	IdleTimeout  int `mapstructure:"idle_timeout"`
 }
 
 type DashScopeConfig struct {
+	// Timeout in seconds for upstream calls made without a caller deadline
 	Timeout int `mapstructure:"timeout"`
+	// EnableITN converts spoken numbers, dates and amounts to written form in requests
+	// that do not set enable_itn
+	EnableITN bool `mapstructure:"enable_itn"`
 }
 
 type UploadConfig struct {
 	MaxFileSize  int64    `mapstructure:"max_file_size"`
 	AllowedTypes []string `mapstructure:"allowed_types"`
+	// MaxDurationSeconds rejects audio longer than this, as stated by its headers. 0 disables the limit.
+	MaxDurationSeconds int `mapstructure:"max_duration_seconds"`
+}
+
+type AdminConfig struct {
+	// Token protects the /admin routes. Admin routes are disabled when empty.
+	Token string `mapstructure:"token"`
+}
+
+type KeysConfig struct {
+	StorePath string `mapstructure:"store_path"`
+	// RequireVirtualKey rejects bearer tokens that are not registered virtual keys
+	// instead of forwarding them to DashScope as-is.
+	RequireVirtualKey bool `mapstructure:"require_virtual_key"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	// Address is the listen address of the metrics server, separate from the API server
+	Address string `mapstructure:"address"`
+	// Models are recorded by name in the model label, in addition to the models routed
+	// or given fallbacks in providers and diarization.model. Other models are recorded
+	// as "other".
+	Models []string `mapstructure:"models"`
+}
+
+type TracingConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	ServiceName string `mapstructure:"service_name"`
+	// Exporter is either "otlp" or "stdout"
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint is the OTLP/HTTP endpoint URL. Standard OTEL_EXPORTER_OTLP_* variables apply when empty.
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error
+	Level string `mapstructure:"level"`
+	// Format is either "text" or "json"
+	Format string `mapstructure:"format"`
+	// Redact removes API keys, prompts and OSS URLs from log output
+	Redact bool `mapstructure:"redact"`
+}
+
+type JobsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"`
+	Workers int    `mapstructure:"workers"`
+	// QueueSize bounds the number of jobs waiting for a worker
+	QueueSize int `mapstructure:"queue_size"`
+	// Timeout in seconds for a single job, including upload and ASR
+	Timeout int `mapstructure:"timeout"`
+	// RetentionHours is how long finished jobs are kept before being deleted
+	RetentionHours int `mapstructure:"retention_hours"`
+}
+
+type WebhooksConfig struct {
+	// Secret signs callback payloads. Job callbacks are disabled when empty.
+	Secret string `mapstructure:"secret"`
+	// MaxAttempts is the number of delivery attempts before a callback is dead-lettered
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// InitialBackoff in seconds before the first retry, doubled after each attempt
+	InitialBackoff int `mapstructure:"initial_backoff"`
+	// Timeout in seconds for a single delivery attempt
+	Timeout int `mapstructure:"timeout"`
+	// DeadLetterPath is the file recording callbacks that could not be delivered
+	DeadLetterPath string `mapstructure:"dead_letter_path"`
+	// AllowedHosts lists the host names, IP addresses and CIDR ranges of internal
+	// receivers. Callbacks to loopback, private, link-local and unspecified addresses are
+	// refused unless their host or address is listed.
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+}
+
+type FilesConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"`
+	// Storage selects the backend for file contents. Only "local", which stores contents in Dir, is available.
+	Storage string `mapstructure:"storage"`
+	// MaxFileSize in bytes for files uploaded through /v1/files
+	MaxFileSize int64 `mapstructure:"max_file_size"`
+}
+
+type BatchesConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"`
+	// Workers is the number of batches processed at the same time
+	Workers int `mapstructure:"workers"`
+	// Concurrency is the number of requests of one batch processed at the same time
+	Concurrency int `mapstructure:"concurrency"`
+	// MaxRequests is the maximum number of lines in a batch input file
+	MaxRequests int `mapstructure:"max_requests"`
+	// QueueSize bounds the number of batches waiting for a worker
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+type ChunkingConfig struct {
+	// Enabled splits WAV and raw PCM audio that exceeds the per-request limits into chunks
+	Enabled bool `mapstructure:"enabled"`
+	// MaxChunkSeconds and MaxChunkSize (bytes) bound a single chunk sent to the ASR model
+	MaxChunkSeconds int   `mapstructure:"max_chunk_seconds"`
+	MaxChunkSize    int64 `mapstructure:"max_chunk_size"`
+	// OverlapSeconds is the audio shared by consecutive chunks
+	OverlapSeconds int `mapstructure:"overlap_seconds"`
+	// SearchSeconds is how far before the chunk limit to look for silence to cut at
+	SearchSeconds int `mapstructure:"search_seconds"`
+	// Concurrency is the number of chunks of one request transcribed at the same time,
+	// across all channels when they are split
+	Concurrency int `mapstructure:"concurrency"`
+	// MaxChannels is the most channels of a recording transcribed with channel_mode=split
+	MaxChannels int `mapstructure:"max_channels"`
+	// PCMSampleRate and PCMChannels describe raw 16-bit interleaved PCM (audio/pcm), for
+	// chunking and silence trimming
+	PCMSampleRate int `mapstructure:"pcm_sample_rate"`
+	PCMChannels   int `mapstructure:"pcm_channels"`
+	// MinSilenceMs is the shortest pause that ends an utterance when transcribing segments
+	MinSilenceMs int `mapstructure:"min_silence_ms"`
+	// MaxSegmentSeconds bounds the length of a single segment
+	MaxSegmentSeconds int `mapstructure:"max_segment_seconds"`
+}
+
+type VADConfig struct {
+	// Enabled trims leading and trailing silence from WAV and raw PCM uploads and shortens
+	// long pauses
+	Enabled bool `mapstructure:"enabled"`
+	// MaxPauseMs is the longest pause kept between two spans of speech
+	MaxPauseMs int `mapstructure:"max_pause_ms"`
+	// PaddingMs is the silence kept before and after speech so that words are not clipped
+	PaddingMs int `mapstructure:"padding_ms"`
+}
+
+type TranscodeConfig struct {
+	// Enabled converts uploads to mono audio with a local ffmpeg before they are sent to OSS
+	Enabled    bool   `mapstructure:"enabled"`
+	FFmpegPath string `mapstructure:"ffmpeg_path"`
+	// Codec is flac or opus
+	Codec      string `mapstructure:"codec"`
+	SampleRate int    `mapstructure:"sample_rate"`
+	// Timeout in seconds of a single ffmpeg run
+	Timeout int `mapstructure:"timeout"`
+}
+
+// Name of the built-in DashScope ASR provider
+const DashScopeProvider = "dashscope"
+
+// Types of configurable ASR providers
+const (
+	// ProviderTypeOpenAI is a server implementing the OpenAI /audio/transcriptions API,
+	// such as a self-hosted whisper or vLLM server
+	ProviderTypeOpenAI = "openai"
+)
+
+// Classes of upstream errors that can fall back to another model
+const (
+	// FallbackServerError is a 5xx response
+	FallbackServerError = "server_error"
+	// FallbackRateLimit is a 429 response
+	FallbackRateLimit = "rate_limit"
+	// FallbackTimeout is an upstream call that timed out
+	FallbackTimeout = "timeout"
+	// FallbackNetwork is an upstream call that failed to connect or was cut off
+	FallbackNetwork = "network"
+)
+
+// IsValidFallbackClass reports whether class is an error class that can fall back
+func IsValidFallbackClass(class string) bool {
+	switch class {
+	case FallbackServerError, FallbackRateLimit, FallbackTimeout, FallbackNetwork:
+		return true
+	}
+	return false
+}
+
+type ProvidersConfig struct {
+	// Backends defines ASR providers by name, in addition to the built-in dashscope provider
+	Backends map[string]ProviderConfig `mapstructure:"backends"`
+	// Models routes model names to providers. Models not listed go to dashscope.
+	Models map[string]string `mapstructure:"models"`
+	// Fallbacks lists, by model, the models tried in order when transcription with the
+	// model fails with an error of a class in FallbackOn
+	Fallbacks map[string][]string `mapstructure:"fallbacks"`
+	// FallbackOn is the error classes that fall back: server_error, rate_limit, timeout
+	// and network
+	FallbackOn []string `mapstructure:"fallback_on"`
+}
+
+type ProviderConfig struct {
+	Type    string `mapstructure:"type"`
+	BaseURL string `mapstructure:"base_url"`
+	APIKey  string `mapstructure:"api_key"`
+	// Model is the model name sent to the provider, the requested model when empty
+	Model string `mapstructure:"model"`
+	// Timeout in seconds of a transcription request, dashscope.timeout when 0
+	Timeout int `mapstructure:"timeout"`
+}
+
+type VocabularyConfig struct {
+	// StorePath is the file persisting vocabularies managed through the admin API. Empty
+	// disables the API; lists defined in the configuration still apply.
+	StorePath string `mapstructure:"store_path"`
+	// Lists defines read-only vocabularies by ID
+	Lists map[string][]string `mapstructure:"lists"`
+	// MaxTerms is the maximum number of terms in a vocabulary
+	MaxTerms int `mapstructure:"max_terms"`
+	// MaxTermLength is the maximum length of a term in characters
+	MaxTermLength int `mapstructure:"max_term_length"`
+	// MaxContextLength bounds the prompt and vocabulary terms sent to the model, in
+	// characters. Terms that do not fit are left out.
+	MaxContextLength int `mapstructure:"max_context_length"`
+}
+
+type ReplacementsConfig struct {
+	// Dictionaries defines find-and-replace rules by ID, applied in order to transcripts
+	// that select the dictionary
+	Dictionaries map[string][]ReplacementRule `mapstructure:"dictionaries"`
+}
+
+type ReplacementRule struct {
+	Find    string `mapstructure:"find"`
+	Replace string `mapstructure:"replace"`
+	// Regex matches Find as a regular expression, whose groups Replace can reference as $1
+	Regex      bool `mapstructure:"regex"`
+	IgnoreCase bool `mapstructure:"ignore_case"`
+}
+
+// Pattern returns the regular expression matching the rule
+func (r ReplacementRule) Pattern() string {
+	pattern := r.Find
+	if !r.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if r.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return pattern
+}
+
+// Mask styles of redacted text
+const (
+	MaskStyleAsterisk = "asterisk"
+	MaskStyleLabel    = "label"
+	MaskStylePartial  = "partial"
+)
+
+type RedactionConfig struct {
+	// MaskStyle is asterisk, label or partial
+	MaskStyle string `mapstructure:"mask_style"`
+	// MaskChar replaces letters and digits with the asterisk and partial styles
+	MaskChar string `mapstructure:"mask_char"`
+	// Profanity lists the words masked by language, replacing the built-in list of the
+	// language. An empty list disables profanity masking for it.
+	Profanity map[string][]string `mapstructure:"profanity"`
+}
+
+type DiarizationConfig struct {
+	// Enabled accepts diarize=true, transcribed by the DashScope file transcription task API
+	Enabled bool `mapstructure:"enabled"`
+	// BaseURL of the DashScope API serving the task endpoints
+	BaseURL string `mapstructure:"base_url"`
+	// Model is the Paraformer model that transcribes and labels speakers
+	Model          string `mapstructure:"model"`
+	PollIntervalMs int    `mapstructure:"poll_interval_ms"`
+	// Timeout in seconds to wait for a task to finish
+	Timeout int `mapstructure:"timeout"`
 }
 
 func Load() (*Config, error) {
@@ -43,7 +340,7 @@ func Load() (*Config, error) {
 	// Read config file if exists
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			fmt.Println("Config file not found, using defaults and environment variables")
+			slog.Info("Config file not found, using defaults and environment variables")
 		} else {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
@@ -52,6 +349,7 @@ func Load() (*Config, error) {
 	// Bind to environment variables
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("QWEN_COMPAT")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Unmarshal config
 	if err := viper.Unmarshal(config); err != nil {
@@ -65,6 +363,10 @@ func InitializeFlags(cmd *cobra.Command) {
 	// Server flags
 	cmd.Flags().String("host", "0.0.0.0", "Server host")
 	cmd.Flags().String("port", "9000", "Server port")
+
+	// Logging flags
+	cmd.Flags().String("log-level", "info", "Log level (debug, info, warn, error)")
+	cmd.Flags().String("log-format", "text", "Log format (text, json)")
 }
 
 // BindFlags binds command flags to viper
@@ -73,13 +375,20 @@ func BindFlags(cmd *cobra.Command) {
 	// Bind flags to viper
 	_ = viper.BindPFlag("server.host", cmd.Flags().Lookup("host"))
 	_ = viper.BindPFlag("server.port", cmd.Flags().Lookup("port"))
+	_ = viper.BindPFlag("log.level", cmd.Flags().Lookup("log-level"))
+	_ = viper.BindPFlag("log.format", cmd.Flags().Lookup("log-format"))
 }
 
 func setDefaults() {
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", "9000")
+	viper.SetDefault("server.read_timeout", 30)
+	viper.SetDefault("server.write_timeout", 30)
+	viper.SetDefault("server.idle_timeout", 60)
 	viper.SetDefault("dashscope.timeout", 30)
+	viper.SetDefault("dashscope.enable_itn", true)
 	viper.SetDefault("upload.max_file_size", 100*1024*1024) // 100MB
+	viper.SetDefault("upload.max_duration_seconds", 0)
 	viper.SetDefault("upload.allowed_types", []string{
 		"audio/aac", "audio/amr", "audio/flac", "audio/mp3", "audio/mpeg",
 		"audio/mp4", "audio/x-m4a", "audio/ogg", "audio/opus",
@@ -88,15 +397,241 @@ func setDefaults() {
 		"video/x-matroska", "video/quicktime", "video/mp4",
 		"video/mpeg", "video/webm", "video/x-ms-wmv",
 	})
+	viper.SetDefault("admin.token", "")
+	viper.SetDefault("keys.store_path", "./data/keys.json")
+	viper.SetDefault("keys.require_virtual_key", false)
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.address", "localhost:9464")
+	viper.SetDefault("metrics.models", []string{"qwen3-asr-flash"})
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.service_name", "qwen3-compatibility")
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.endpoint", "")
+	viper.SetDefault("tracing.insecure", false)
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("log.redact", true)
+	viper.SetDefault("jobs.enabled", true)
+	viper.SetDefault("jobs.dir", "./data/jobs")
+	viper.SetDefault("jobs.workers", 2)
+	viper.SetDefault("jobs.queue_size", 100)
+	viper.SetDefault("jobs.timeout", 7200)
+	viper.SetDefault("jobs.retention_hours", 24)
+	viper.SetDefault("webhooks.secret", "")
+	viper.SetDefault("webhooks.max_attempts", 5)
+	viper.SetDefault("webhooks.initial_backoff", 2)
+	viper.SetDefault("webhooks.timeout", 10)
+	viper.SetDefault("webhooks.dead_letter_path", "./data/webhooks_dead_letter.json")
+	viper.SetDefault("webhooks.allowed_hosts", []string{})
+	viper.SetDefault("files.enabled", true)
+	viper.SetDefault("files.dir", "./data/files")
+	viper.SetDefault("files.storage", "local")
+	viper.SetDefault("files.max_file_size", 200*1024*1024) // 200MB
+	viper.SetDefault("batches.enabled", true)
+	viper.SetDefault("batches.dir", "./data/batches")
+	viper.SetDefault("batches.workers", 1)
+	viper.SetDefault("batches.concurrency", 4)
+	viper.SetDefault("batches.max_requests", 50000)
+	viper.SetDefault("batches.queue_size", 1000)
+	viper.SetDefault("chunking.enabled", true)
+	viper.SetDefault("chunking.max_chunk_seconds", 180)
+	viper.SetDefault("chunking.max_chunk_size", 10*1024*1024) // 10MB
+	viper.SetDefault("chunking.overlap_seconds", 2)
+	viper.SetDefault("chunking.search_seconds", 15)
+	viper.SetDefault("chunking.concurrency", 4)
+	viper.SetDefault("chunking.max_channels", 8)
+	viper.SetDefault("chunking.pcm_sample_rate", 16000)
+	viper.SetDefault("chunking.pcm_channels", 1)
+	viper.SetDefault("chunking.min_silence_ms", 500)
+	viper.SetDefault("chunking.max_segment_seconds", 30)
+	viper.SetDefault("vad.enabled", true)
+	viper.SetDefault("vad.max_pause_ms", 1000)
+	viper.SetDefault("vad.padding_ms", 200)
+	viper.SetDefault("transcode.enabled", false)
+	viper.SetDefault("transcode.ffmpeg_path", "ffmpeg")
+	viper.SetDefault("transcode.codec", "flac")
+	viper.SetDefault("transcode.sample_rate", 16000)
+	viper.SetDefault("transcode.timeout", 120)
+	viper.SetDefault("providers.fallback_on", []string{FallbackServerError, FallbackTimeout, FallbackNetwork})
+
+	viper.SetDefault("vocabulary.store_path", "./data/vocabularies.json")
+	viper.SetDefault("vocabulary.max_terms", 500)
+	viper.SetDefault("vocabulary.max_term_length", 64)
+	viper.SetDefault("vocabulary.max_context_length", 2000)
+
+	viper.SetDefault("redaction.mask_style", MaskStyleAsterisk)
+	viper.SetDefault("redaction.mask_char", "*")
+
+	viper.SetDefault("diarization.enabled", false)
+	viper.SetDefault("diarization.base_url", "https://dashscope.aliyuncs.com/api/v1")
+	viper.SetDefault("diarization.model", "paraformer-v2")
+	viper.SetDefault("diarization.poll_interval_ms", 1000)
+	viper.SetDefault("diarization.timeout", 600)
 }
 
 func (c *Config) Validate() error {
 	if c.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
+	if c.Keys.RequireVirtualKey && c.Keys.StorePath == "" {
+		return fmt.Errorf("keys.store_path is required when keys.require_virtual_key is enabled")
+	}
+	if c.Metrics.Enabled && (c.Metrics.Address == "" || !strings.HasPrefix(c.Metrics.Path, "/")) {
+		return fmt.Errorf("metrics.address and a metrics.path starting with / are required when metrics are enabled")
+	}
+	if c.Tracing.Enabled && c.Tracing.Exporter != "otlp" && c.Tracing.Exporter != "stdout" {
+		return fmt.Errorf("tracing.exporter must be otlp or stdout, got %q", c.Tracing.Exporter)
+	}
+	if c.Jobs.Enabled && (c.Jobs.Dir == "" || c.Jobs.Workers < 1 || c.Jobs.QueueSize < 1) {
+		return fmt.Errorf("jobs.dir, jobs.workers and jobs.queue_size are required when jobs are enabled")
+	}
+	if c.Webhooks.Secret != "" && (c.Webhooks.MaxAttempts < 1 || c.Webhooks.Timeout < 1 || c.Webhooks.DeadLetterPath == "") {
+		return fmt.Errorf("webhooks.max_attempts, webhooks.timeout and webhooks.dead_letter_path are required when webhooks.secret is set")
+	}
+	for _, host := range c.Webhooks.AllowedHosts {
+		if host == "" {
+			return fmt.Errorf("webhooks.allowed_hosts must not contain empty entries")
+		}
+		if strings.Contains(host, "/") {
+			if _, err := netip.ParsePrefix(host); err != nil {
+				return fmt.Errorf("webhooks.allowed_hosts: invalid CIDR range %q", host)
+			}
+		}
+	}
+	if c.Files.Enabled && (c.Files.Dir == "" || c.Files.MaxFileSize < 1) {
+		return fmt.Errorf("files.dir and files.max_file_size are required when files are enabled")
+	}
+	if c.Batches.Enabled && !c.Files.Enabled {
+		return fmt.Errorf("batches require files to be enabled")
+	}
+	if c.Batches.Enabled && (c.Batches.Dir == "" || c.Batches.Workers < 1 || c.Batches.Concurrency < 1 || c.Batches.MaxRequests < 1 || c.Batches.QueueSize < 1) {
+		return fmt.Errorf("batches.dir, batches.workers, batches.concurrency, batches.max_requests and batches.queue_size are required when batches are enabled")
+	}
+	if c.Chunking.Enabled && (c.Chunking.MaxChunkSeconds < 1 || c.Chunking.MaxChunkSize < 1 || c.Chunking.Concurrency < 1) {
+		return fmt.Errorf("chunking.max_chunk_seconds, chunking.max_chunk_size and chunking.concurrency are required when chunking is enabled")
+	}
+	if (c.Chunking.Enabled || c.VAD.Enabled) && (c.Chunking.PCMSampleRate < 1 || c.Chunking.PCMChannels < 1) {
+		return fmt.Errorf("chunking.pcm_sample_rate and chunking.pcm_channels are required when chunking or vad is enabled")
+	}
+	if c.Chunking.Enabled && c.Chunking.MaxChannels < 2 {
+		return fmt.Errorf("chunking.max_channels must be at least 2 when chunking is enabled")
+	}
+	if c.Chunking.Enabled && (c.Chunking.MinSilenceMs < 1 || c.Chunking.MaxSegmentSeconds < 1) {
+		return fmt.Errorf("chunking.min_silence_ms and chunking.max_segment_seconds are required when chunking is enabled")
+	}
+	if c.Chunking.Enabled && (c.Chunking.OverlapSeconds < 0 || c.Chunking.SearchSeconds < 0 || 2*c.Chunking.OverlapSeconds+c.Chunking.SearchSeconds >= c.Chunking.MaxChunkSeconds) {
+		return fmt.Errorf("chunking.overlap_seconds and chunking.search_seconds must leave room in chunking.max_chunk_seconds")
+	}
+	if c.Upload.MaxDurationSeconds < 0 {
+		return fmt.Errorf("upload.max_duration_seconds must not be negative")
+	}
+	if c.VAD.Enabled && (c.VAD.MaxPauseMs < 1 || c.VAD.PaddingMs < 0) {
+		return fmt.Errorf("vad.max_pause_ms is required and vad.padding_ms must not be negative when vad is enabled")
+	}
+	if c.Transcode.Enabled && c.Transcode.Codec != "flac" && c.Transcode.Codec != "opus" {
+		return fmt.Errorf("transcode.codec must be flac or opus, got %q", c.Transcode.Codec)
+	}
+	if c.Transcode.Enabled && (c.Transcode.FFmpegPath == "" || c.Transcode.Timeout < 1) {
+		return fmt.Errorf("transcode.ffmpeg_path and transcode.timeout are required when transcoding is enabled")
+	}
+	if c.Transcode.Enabled && c.Transcode.Codec == "opus" && !slices.Contains([]int{8000, 12000, 16000, 24000, 48000}, c.Transcode.SampleRate) {
+		return fmt.Errorf("transcode.sample_rate must be 8000, 12000, 16000, 24000 or 48000 for opus")
+	}
+	if c.Transcode.Enabled && c.Transcode.SampleRate < 1 {
+		return fmt.Errorf("transcode.sample_rate is required when transcoding is enabled")
+	}
+	if c.Diarization.Enabled && (c.Diarization.BaseURL == "" || c.Diarization.Model == "" || c.Diarization.PollIntervalMs < 1 || c.Diarization.Timeout < 1) {
+		return fmt.Errorf("diarization.base_url, diarization.model, diarization.poll_interval_ms and diarization.timeout are required when diarization is enabled")
+	}
+	for name, backend := range c.Providers.Backends {
+		if name == DashScopeProvider {
+			return fmt.Errorf("providers.backends.%s: %s is the built-in provider", name, DashScopeProvider)
+		}
+		if backend.Type != ProviderTypeOpenAI {
+			return fmt.Errorf("providers.backends.%s.type must be %s, got %q", name, ProviderTypeOpenAI, backend.Type)
+		}
+		if backend.BaseURL == "" {
+			return fmt.Errorf("providers.backends.%s.base_url is required", name)
+		}
+		if backend.Timeout < 0 {
+			return fmt.Errorf("providers.backends.%s.timeout must not be negative", name)
+		}
+	}
+	for model, provider := range c.Providers.Models {
+		if _, ok := c.Providers.Backends[provider]; !ok && provider != DashScopeProvider {
+			return fmt.Errorf("providers.models.%s: unknown provider %q", model, provider)
+		}
+	}
+	for model, fallbacks := range c.Providers.Fallbacks {
+		for _, fallback := range fallbacks {
+			if fallback == "" || strings.EqualFold(fallback, model) {
+				return fmt.Errorf("providers.fallbacks.%s: fallback models must be other, non-empty model names", model)
+			}
+		}
+	}
+	for _, class := range c.Providers.FallbackOn {
+		if !IsValidFallbackClass(class) {
+			return fmt.Errorf("providers.fallback_on: unknown error class %q", class)
+		}
+	}
+	if c.Vocabulary.MaxTerms < 1 || c.Vocabulary.MaxTermLength < 1 || c.Vocabulary.MaxContextLength < 1 {
+		return fmt.Errorf("vocabulary.max_terms, vocabulary.max_term_length and vocabulary.max_context_length must be positive")
+	}
+	for id, terms := range c.Vocabulary.Lists {
+		if len(terms) > c.Vocabulary.MaxTerms {
+			return fmt.Errorf("vocabulary.lists.%s has more than %d terms", id, c.Vocabulary.MaxTerms)
+		}
+		for _, term := range terms {
+			if utf8.RuneCountInString(strings.TrimSpace(term)) > c.Vocabulary.MaxTermLength {
+				return fmt.Errorf("vocabulary.lists.%s: term %q is longer than %d characters", id, term, c.Vocabulary.MaxTermLength)
+			}
+		}
+	}
+	for id, rules := range c.Replacements.Dictionaries {
+		for i, rule := range rules {
+			if rule.Find == "" {
+				return fmt.Errorf("replacements.dictionaries.%s[%d]: find is required", id, i)
+			}
+			re, err := regexp.Compile(rule.Pattern())
+			if err != nil {
+				return fmt.Errorf("replacements.dictionaries.%s[%d]: invalid pattern: %w", id, i, err)
+			}
+			if re.MatchString("") {
+				return fmt.Errorf("replacements.dictionaries.%s[%d]: pattern must not match empty text", id, i)
+			}
+		}
+	}
+	if c.Redaction.MaskStyle != MaskStyleAsterisk && c.Redaction.MaskStyle != MaskStyleLabel && c.Redaction.MaskStyle != MaskStylePartial {
+		return fmt.Errorf("redaction.mask_style must be asterisk, label or partial, got %q", c.Redaction.MaskStyle)
+	}
+	if utf8.RuneCountInString(c.Redaction.MaskChar) != 1 {
+		return fmt.Errorf("redaction.mask_char must be a single character")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+	}
 	return nil
 }
 
 func (c *Config) GetServerAddress() string {
 	return c.Server.Host + ":" + c.Server.Port
 }
+
+// MetricModels returns the model names recorded by name in metrics: metrics.models, the
+// models routed or given fallbacks in providers, and diarization.model
+func (c *Config) MetricModels() []string {
+	models := slices.Clone(c.Metrics.Models)
+	for model := range c.Providers.Models {
+		models = append(models, model)
+	}
+	for model, fallbacks := range c.Providers.Fallbacks {
+		models = append(models, model)
+		models = append(models, fallbacks...)
+	}
+	if c.Diarization.Enabled {
+		models = append(models, c.Diarization.Model)
+	}
+	return models
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -10,6 +11,12 @@ type APIError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+	// UpstreamCode is the error code reported by an upstream service, if any
+	UpstreamCode string `json:"-"`
+	// UpstreamStatus is the HTTP status returned by an upstream service, if any
+	UpstreamStatus int `json:"-"`
+	// UpstreamRequestID is the request ID reported by an upstream service, if any
+	UpstreamRequestID string `json:"-"`
 }
 
 func (e *APIError) Error() string {
@@ -27,6 +34,13 @@ func NewInternalServerError(message string) *APIError {
 		Message: message,
 	}
 }
+func NewValidationError(message string) *APIError {
+	return &APIError{
+		Code:    http.StatusBadRequest,
+		Message: message,
+	}
+}
+
 func NewFileSizeError(maxSize int64) *APIError {
 	return &APIError{
 		Code:    http.StatusBadRequest,
@@ -41,6 +55,21 @@ func NewFileTypeError(allowedTypes []string) *APIError {
 		Details: fmt.Sprintf("Allowed types: %v", allowedTypes),
 	}
 }
+
+func NewDurationError(maxSeconds int) *APIError {
+	return &APIError{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("Audio too long. Maximum duration is %d seconds", maxSeconds),
+	}
+}
+
+func NewFileTypeMismatchError(declared, detected string) *APIError {
+	return &APIError{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("File content is %s but the file was sent as %s", detected, declared),
+	}
+}
+
 func NewExternalServiceError(service string, details string) *APIError {
 	return &APIError{
 		Code:    http.StatusBadGateway,
@@ -57,9 +86,10 @@ func NewUploadError(details string) *APIError {
 	}
 }
 
-// Check if error is APIError
+// Check if error is or wraps an APIError
 func IsAPIError(err error) (*APIError, bool) {
-	if apiErr, ok := err.(*APIError); ok {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
 		return apiErr, true
 	}
 	return nil, false
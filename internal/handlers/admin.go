@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/services"
+	"qwen3-compatibility/internal/store"
+)
+
+type AdminHandler struct {
+	keyStore           *store.KeyStore
+	postProcessService services.IPostProcessService
+}
+
+func NewAdminHandler(keyStore *store.KeyStore, postProcessService services.IPostProcessService) *AdminHandler {
+	return &AdminHandler{
+		keyStore:           keyStore,
+		postProcessService: postProcessService,
+	}
+}
+
+// CreateKey handles POST /admin/keys
+func (h *AdminHandler) CreateKey(c *gin.Context) {
+	var req models.CreateVirtualKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: name and upstream_key are required",
+		})
+		return
+	}
+	if !validLimits(req.Limits) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Limits must not be negative",
+		})
+		return
+	}
+
+	if req.CallbackURL != "" && !validCallbackURL(req.CallbackURL) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "callback_url must be an absolute http or https URL",
+		})
+		return
+	}
+	if !validVocabularyIDs(req.VocabularyIDs) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: vocabularyIDError,
+		})
+		return
+	}
+	if !validDictionaryIDs(h.postProcessService, req.DictionaryIDs) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: dictionaryIDError,
+		})
+		return
+	}
+
+	key, secret, err := h.keyStore.Create(&req)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to create virtual key", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create virtual key",
+		})
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("Virtual key created", "virtual_key_id", key.ID, "name", key.Name)
+
+	response := toVirtualKeyResponse(key)
+	response.Key = secret
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListKeys handles GET /admin/keys
+func (h *AdminHandler) ListKeys(c *gin.Context) {
+	keys := h.keyStore.List()
+
+	response := models.VirtualKeyListResponse{
+		Object: "list",
+		Data:   make([]models.VirtualKeyResponse, 0, len(keys)),
+	}
+	for i := range keys {
+		response.Data = append(response.Data, toVirtualKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetKey handles GET /admin/keys/:id
+func (h *AdminHandler) GetKey(c *gin.Context) {
+	key, err := h.keyStore.Get(c.Param("id"))
+	if err != nil {
+		h.respondStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toVirtualKeyResponse(key))
+}
+
+// UpdateKey handles PATCH /admin/keys/:id
+func (h *AdminHandler) UpdateKey(c *gin.Context) {
+	var req models.UpdateVirtualKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+		return
+	}
+	if req.Name != nil && *req.Name == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "name must not be empty",
+		})
+		return
+	}
+	if req.UpstreamKey != nil && *req.UpstreamKey == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "upstream_key must not be empty",
+		})
+		return
+	}
+	if req.Limits != nil && !validLimits(*req.Limits) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Limits must not be negative",
+		})
+		return
+	}
+	if req.CallbackURL != nil && *req.CallbackURL != "" && !validCallbackURL(*req.CallbackURL) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "callback_url must be an absolute http or https URL",
+		})
+		return
+	}
+	if req.VocabularyIDs != nil && !validVocabularyIDs(*req.VocabularyIDs) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: vocabularyIDError,
+		})
+		return
+	}
+	if req.DictionaryIDs != nil && !validDictionaryIDs(h.postProcessService, *req.DictionaryIDs) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: dictionaryIDError,
+		})
+		return
+	}
+
+	key, err := h.keyStore.Update(c.Param("id"), func(key *models.VirtualKey) {
+		if req.Name != nil {
+			key.Name = *req.Name
+		}
+		if req.UpstreamKey != nil {
+			key.UpstreamKey = *req.UpstreamKey
+		}
+		if req.AllowedModels != nil {
+			key.AllowedModels = *req.AllowedModels
+		}
+		if req.Limits != nil {
+			key.Limits = *req.Limits
+		}
+		if req.CallbackURL != nil {
+			key.CallbackURL = *req.CallbackURL
+		}
+		if req.VocabularyIDs != nil {
+			key.VocabularyIDs = *req.VocabularyIDs
+		}
+		if req.DictionaryIDs != nil {
+			key.DictionaryIDs = *req.DictionaryIDs
+		}
+		if req.Disabled != nil {
+			key.Disabled = *req.Disabled
+		}
+	})
+	if err != nil {
+		h.respondStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toVirtualKeyResponse(key))
+}
+
+// RotateKey handles POST /admin/keys/:id/rotate
+func (h *AdminHandler) RotateKey(c *gin.Context) {
+	key, secret, err := h.keyStore.Rotate(c.Param("id"))
+	if err != nil {
+		h.respondStoreError(c, err)
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("Virtual key rotated", "virtual_key_id", key.ID)
+
+	response := toVirtualKeyResponse(key)
+	response.Key = secret
+	c.JSON(http.StatusOK, response)
+}
+
+// DisableKey handles POST /admin/keys/:id/disable
+func (h *AdminHandler) DisableKey(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+// EnableKey handles POST /admin/keys/:id/enable
+func (h *AdminHandler) EnableKey(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+// DeleteKey handles DELETE /admin/keys/:id
+func (h *AdminHandler) DeleteKey(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.keyStore.Delete(id); err != nil {
+		h.respondStoreError(c, err)
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("Virtual key deleted", "virtual_key_id", id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "virtual_key",
+		"deleted": true,
+	})
+}
+
+func (h *AdminHandler) setDisabled(c *gin.Context, disabled bool) {
+	key, err := h.keyStore.Update(c.Param("id"), func(key *models.VirtualKey) {
+		key.Disabled = disabled
+	})
+	if err != nil {
+		h.respondStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toVirtualKeyResponse(key))
+}
+
+func (h *AdminHandler) respondStoreError(c *gin.Context, err error) {
+	if errors.Is(err, store.ErrKeyNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Virtual key not found",
+		})
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Error("Key store operation failed", "error", err)
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Error: "Key store operation failed",
+	})
+}
+
+func validLimits(limits models.KeyLimits) bool {
+	return limits.RequestsPerMinute >= 0 && limits.MaxFileSize >= 0 && limits.MaxDurationSeconds >= 0
+}
+
+func toVirtualKeyResponse(key *models.VirtualKey) models.VirtualKeyResponse {
+	response := models.VirtualKeyResponse{
+		ID:            key.ID,
+		Object:        "virtual_key",
+		Name:          key.Name,
+		KeyPrefix:     key.KeyPrefix,
+		UpstreamKey:   maskSecret(key.UpstreamKey),
+		AllowedModels: key.AllowedModels,
+		Limits:        key.Limits,
+		CallbackURL:   key.CallbackURL,
+		VocabularyIDs: key.VocabularyIDs,
+		DictionaryIDs: key.DictionaryIDs,
+		Disabled:      key.Disabled,
+		CreatedAt:     key.CreatedAt.Unix(),
+		UpdatedAt:     key.UpdatedAt.Unix(),
+	}
+	if response.AllowedModels == nil {
+		response.AllowedModels = []string{}
+	}
+	if response.VocabularyIDs == nil {
+		response.VocabularyIDs = []string{}
+	}
+	if response.DictionaryIDs == nil {
+		response.DictionaryIDs = []string{}
+	}
+	if !key.RotatedAt.IsZero() {
+		response.RotatedAt = key.RotatedAt.Unix()
+	}
+	return response
+}
+
+// maskSecret keeps only the last four characters of a secret
+func maskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
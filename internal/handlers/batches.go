@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/middleware"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+	"qwen3-compatibility/internal/services"
+)
+
+const (
+	defaultBatchListLimit = 20
+	maxBatchListLimit     = 100
+)
+
+type BatchesHandler struct {
+	batchService services.IBatchService
+}
+
+func NewBatchesHandler(batchService services.IBatchService) *BatchesHandler {
+	return &BatchesHandler{
+		batchService: batchService,
+	}
+}
+
+// CreateBatch handles POST /v1/batches
+func (h *BatchesHandler) CreateBatch(c *gin.Context) {
+	var req models.CreateBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: input_file_id, endpoint and completion_window are required",
+		})
+		return
+	}
+
+	caller := requestid.Caller{APIKey: c.GetString(middleware.APIKeyContextKey)}
+	if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+		caller = requestid.Caller{VirtualKeyID: virtualKey.ID}
+	}
+
+	batch, err := h.batchService.Create(c.Request.Context(), &req, middleware.OwnerID(c), caller)
+	if err != nil {
+		if apiErr, ok := errors.IsAPIError(err); ok {
+			c.JSON(apiErr.HTTPStatus(), models.ErrorResponse{
+				Error: apiErr.Message,
+			})
+			return
+		}
+		if stderrors.Is(err, services.ErrBatchQueueFull) {
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error: "Batch queue is full, try again later",
+			})
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("Failed to create batch", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create batch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewBatchResponse(batch))
+}
+
+// GetBatch handles GET /v1/batches/:id
+func (h *BatchesHandler) GetBatch(c *gin.Context) {
+	batch, err := h.batchService.Get(c.Param("id"), middleware.OwnerID(c))
+	if err != nil {
+		respondBatchNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewBatchResponse(batch))
+}
+
+// CancelBatch handles POST /v1/batches/:id/cancel
+func (h *BatchesHandler) CancelBatch(c *gin.Context) {
+	batch, err := h.batchService.Cancel(c.Param("id"), middleware.OwnerID(c))
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, models.NewBatchResponse(batch))
+	case stderrors.Is(err, services.ErrBatchFinished):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: "Batch has already finished",
+		})
+	case stderrors.Is(err, services.ErrBatchNotFound):
+		respondBatchNotFound(c)
+	default:
+		logging.FromContext(c.Request.Context()).Error("Failed to cancel batch", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to cancel batch",
+		})
+	}
+}
+
+// ListBatches handles GET /v1/batches with OpenAI-style after/limit pagination
+func (h *BatchesHandler) ListBatches(c *gin.Context) {
+	limit := defaultBatchListLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxBatchListLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "limit must be between 1 and 100",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	batches := h.batchService.List(middleware.OwnerID(c))
+	if after := c.Query("after"); after != "" {
+		for i := range batches {
+			if batches[i].ID == after {
+				batches = batches[i+1:]
+				break
+			}
+		}
+	}
+
+	response := models.BatchListResponse{
+		Object:  "list",
+		Data:    make([]models.BatchResponse, 0, limit),
+		HasMore: len(batches) > limit,
+	}
+	if len(batches) > limit {
+		batches = batches[:limit]
+	}
+	for i := range batches {
+		response.Data = append(response.Data, models.NewBatchResponse(&batches[i]))
+	}
+	if len(response.Data) > 0 {
+		response.FirstID = &response.Data[0].ID
+		response.LastID = &response.Data[len(response.Data)-1].ID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondBatchNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error: "Batch not found",
+	})
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/middleware"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/services"
+	"qwen3-compatibility/internal/store"
+)
+
+const (
+	defaultFileListLimit = 10000
+	maxFileListLimit     = 10000
+)
+
+// Purposes accepted for uploaded files
+var uploadPurposes = map[string]bool{
+	models.FilePurposeBatch:    true,
+	models.FilePurposeUserData: true,
+}
+
+type FilesHandler struct {
+	fileService services.IFileService
+	maxFileSize int64
+}
+
+func NewFilesHandler(fileService services.IFileService, maxFileSize int64) *FilesHandler {
+	return &FilesHandler{
+		fileService: fileService,
+		maxFileSize: maxFileSize,
+	}
+}
+
+// UploadFile handles POST /v1/files
+func (h *FilesHandler) UploadFile(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Failed to get file from form",
+		})
+		return
+	}
+	defer closeFormFile(c, file)
+
+	purpose := c.PostForm("purpose")
+	if !uploadPurposes[purpose] {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "purpose must be batch or user_data",
+		})
+		return
+	}
+	if header.Size > h.maxFileSize {
+		h.respondFileTooLarge(c)
+		return
+	}
+
+	stored := &models.File{
+		Filename:    header.Filename,
+		Purpose:     purpose,
+		ContentType: header.Header.Get("Content-Type"),
+		Owner:       middleware.OwnerID(c),
+	}
+	if err := h.fileService.Create(c.Request.Context(), stored, file, h.maxFileSize); err != nil {
+		if stderrors.Is(err, store.ErrFileTooLarge) {
+			h.respondFileTooLarge(c)
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("Failed to store file", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to store file",
+		})
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("File uploaded", "file_id", stored.ID, "purpose", purpose, "size", stored.Bytes)
+
+	c.JSON(http.StatusOK, models.NewFileResponse(stored))
+}
+
+// ListFiles handles GET /v1/files with OpenAI-style purpose, order, after and limit parameters
+func (h *FilesHandler) ListFiles(c *gin.Context) {
+	limit := defaultFileListLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxFileListLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "limit must be between 1 and 10000",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	order := c.DefaultQuery("order", "desc")
+	if order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "order must be asc or desc",
+		})
+		return
+	}
+
+	files := h.fileService.List(middleware.OwnerID(c), c.Query("purpose"))
+	if order == "desc" {
+		for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+			files[i], files[j] = files[j], files[i]
+		}
+	}
+	if after := c.Query("after"); after != "" {
+		for i := range files {
+			if files[i].ID == after {
+				files = files[i+1:]
+				break
+			}
+		}
+	}
+
+	response := models.FileListResponse{
+		Object:  "list",
+		Data:    make([]models.FileResponse, 0, min(limit, len(files))),
+		HasMore: len(files) > limit,
+	}
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	for i := range files {
+		response.Data = append(response.Data, models.NewFileResponse(&files[i]))
+	}
+	if len(response.Data) > 0 {
+		response.FirstID = &response.Data[0].ID
+		response.LastID = &response.Data[len(response.Data)-1].ID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFile handles GET /v1/files/:id
+func (h *FilesHandler) GetFile(c *gin.Context) {
+	file, err := h.fileService.Get(c.Param("id"), middleware.OwnerID(c))
+	if err != nil {
+		respondFileNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewFileResponse(file))
+}
+
+// GetFileContent handles GET /v1/files/:id/content
+func (h *FilesHandler) GetFileContent(c *gin.Context) {
+	owner := middleware.OwnerID(c)
+	file, err := h.fileService.Get(c.Param("id"), owner)
+	if err != nil {
+		respondFileNotFound(c)
+		return
+	}
+
+	content, err := h.fileService.Open(c.Request.Context(), file.ID, owner)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to open file", "file_id", file.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to read file",
+		})
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(http.StatusOK, file.Bytes, contentType, content, map[string]string{
+		"Content-Disposition": `attachment; filename="` + file.ID + `"`,
+	})
+}
+
+// DeleteFile handles DELETE /v1/files/:id
+func (h *FilesHandler) DeleteFile(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.fileService.Delete(c.Request.Context(), id, middleware.OwnerID(c)); err != nil {
+		if stderrors.Is(err, services.ErrFileNotFound) {
+			respondFileNotFound(c)
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("Failed to delete file", "file_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to delete file",
+		})
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("File deleted", "file_id", id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "file",
+		"deleted": true,
+	})
+}
+
+func (h *FilesHandler) respondFileTooLarge(c *gin.Context) {
+	apiErr := errors.NewFileSizeError(h.maxFileSize)
+	c.JSON(apiErr.HTTPStatus(), models.ErrorResponse{
+		Error: apiErr.Message,
+	})
+}
+
+func respondFileNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error: "File not found",
+	})
+}
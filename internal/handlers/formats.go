@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"qwen3-compatibility/internal/models"
+)
+
+// formatSRT renders segments as SubRip subtitles. Segments of split channels or labeled
+// speakers are prefixed with their label.
+func formatSRT(segments []models.TranscriptionSegment) string {
+	var b strings.Builder
+	for i, segment := range segments {
+		text := segment.Text
+		if label := segment.Label(); label != "" {
+			text = fmt.Sprintf("[%s] %s", label, text)
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1,
+			subtitleTimestamp(segment.Start, ","), subtitleTimestamp(segment.End, ","), text)
+	}
+	return b.String()
+}
+
+// formatVTT renders segments as WebVTT subtitles. Segments of split channels or labeled
+// speakers are voiced by their label.
+func formatVTT(segments []models.TranscriptionSegment) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, segment := range segments {
+		text := segment.Text
+		if label := segment.Label(); label != "" {
+			text = fmt.Sprintf("<v %s>%s", label, text)
+		}
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n",
+			subtitleTimestamp(segment.Start, "."), subtitleTimestamp(segment.End, "."), text)
+	}
+	return b.String()
+}
+
+// subtitleTimestamp formats seconds as HH:MM:SS followed by the millisecond separator and milliseconds
+func subtitleTimestamp(seconds float64, separator string) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/middleware"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/services"
+)
+
+type JobsHandler struct {
+	uploadService      services.IUploadService
+	fileService        services.IFileService
+	jobService         services.IJobService
+	vocabularyService  services.IVocabularyService
+	postProcessService services.IPostProcessService
+	callbacksEnabled   bool
+	// diarizationModel is the model of diarized jobs, empty when diarization is disabled
+	diarizationModel string
+}
+
+// NewJobsHandler creates the jobs handler. fileService may be nil when the Files API is
+// disabled, and diarizationModel is empty when diarization is disabled.
+func NewJobsHandler(uploadService services.IUploadService, fileService services.IFileService, jobService services.IJobService, vocabularyService services.IVocabularyService, postProcessService services.IPostProcessService, callbacksEnabled bool, diarizationModel string) *JobsHandler {
+	return &JobsHandler{
+		uploadService:      uploadService,
+		fileService:        fileService,
+		jobService:         jobService,
+		vocabularyService:  vocabularyService,
+		postProcessService: postProcessService,
+		callbacksEnabled:   callbacksEnabled,
+		diarizationModel:   diarizationModel,
+	}
+}
+
+// CreateJob handles POST /v1/audio/transcriptions/jobs
+func (h *JobsHandler) CreateJob(c *gin.Context) {
+	req, ok := bindTranscriptionRequest(c, h.fileService)
+	if !ok {
+		return
+	}
+	defer closeFormFile(c, req.file)
+
+	// Vocabularies are resolved on submission, so later changes do not affect queued jobs
+	if !applyVocabulary(c, h.vocabularyService, req) || !applyDictionaries(c, h.postProcessService, req) {
+		return
+	}
+
+	// Job results are always JSON; verbose_json adds segment timestamps
+	if format := req.params.ResponseFormat; format != models.ResponseFormatJSON && format != models.ResponseFormatVerboseJSON {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "response_format must be json or verbose_json for jobs",
+		})
+		return
+	}
+
+	if req.params.Diarize && h.diarizationModel == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: services.ErrDiarizationDisabled.Error(),
+		})
+		return
+	}
+	if req.params.Diarize && !req.params.AllowsModel(h.diarizationModel) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: services.ErrDiarizationModelNotAllowed.Error(),
+		})
+		return
+	}
+
+	callbackURL, ok := h.callbackURL(c)
+	if !ok {
+		return
+	}
+
+	// Reject invalid files now rather than when the job runs
+	if !validateRequestFile(c, h.uploadService, h.fileService, req) {
+		return
+	}
+
+	var audio io.Reader = req.file
+	if req.storedFile != nil {
+		content, err := h.fileService.Open(c.Request.Context(), req.storedFile.ID, middleware.OwnerID(c))
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to open stored file", "file_id", req.storedFile.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to read file",
+			})
+			return
+		}
+		defer func() { _ = content.Close() }()
+		audio = content
+	}
+
+	submission := &services.JobSubmission{
+		Params:      req.params,
+		Audio:       audio,
+		FileName:    req.header.Filename,
+		FileSize:    req.header.Size,
+		ContentType: req.header.Header.Get("Content-Type"),
+		Owner:       middleware.OwnerID(c),
+		APIKey:      req.apiKey,
+		RequestID:   c.GetString(middleware.RequestIDContextKey),
+		CallbackURL: callbackURL,
+	}
+	if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+		submission.VirtualKeyID = virtualKey.ID
+	}
+
+	job, err := h.jobService.Submit(c.Request.Context(), submission)
+	if err != nil {
+		if stderrors.Is(err, services.ErrJobQueueFull) {
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error: "Job queue is full, try again later",
+			})
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("Failed to submit transcription job", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create job",
+		})
+		return
+	}
+
+	c.Header("Location", "/v1/audio/transcriptions/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, models.NewTranscriptionJobResponse(job))
+}
+
+// GetJob handles GET /v1/audio/transcriptions/jobs/:id
+func (h *JobsHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.Get(c.Param("id"), middleware.OwnerID(c))
+	if err != nil {
+		respondJobNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewTranscriptionJobResponse(job))
+}
+
+// CancelJob handles DELETE /v1/audio/transcriptions/jobs/:id. Queued and running jobs are
+// cancelled; finished jobs are deleted.
+func (h *JobsHandler) CancelJob(c *gin.Context) {
+	id := c.Param("id")
+	owner := middleware.OwnerID(c)
+
+	job, err := h.jobService.Cancel(id, owner)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, models.NewTranscriptionJobResponse(job))
+	case stderrors.Is(err, services.ErrJobFinished):
+		if err := h.jobService.Delete(id, owner); err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to delete transcription job", "job_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to delete job",
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"id":      id,
+			"object":  "transcription.job",
+			"deleted": true,
+		})
+	case stderrors.Is(err, services.ErrJobNotFound):
+		respondJobNotFound(c)
+	default:
+		logging.FromContext(c.Request.Context()).Error("Failed to cancel transcription job", "job_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to cancel job",
+		})
+	}
+}
+
+// callbackURL returns the callback URL for a new job: the callback_url form field, or
+// the default of the virtual key. It writes an error response and returns false on failure.
+func (h *JobsHandler) callbackURL(c *gin.Context) (string, bool) {
+	callbackURL := c.PostForm("callback_url")
+	if callbackURL == "" {
+		if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil && h.callbacksEnabled {
+			callbackURL = virtualKey.CallbackURL
+		}
+		return callbackURL, true
+	}
+
+	if !h.callbacksEnabled {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Callbacks are not enabled on this server",
+		})
+		return "", false
+	}
+	if !validCallbackURL(callbackURL) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "callback_url must be an absolute http or https URL",
+		})
+		return "", false
+	}
+
+	return callbackURL, true
+}
+
+func respondJobNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error: "Job not found",
+	})
+}
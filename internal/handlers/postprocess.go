@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/middleware"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/services"
+)
+
+const dictionaryIDError = "Dictionary IDs must name configured dictionaries"
+
+// validDictionaryIDs reports whether all the dictionaries are configured. They cannot be
+// added at run time.
+func validDictionaryIDs(postProcessService services.IPostProcessService, ids []string) bool {
+	for _, id := range ids {
+		if !postProcessService.HasDictionary(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDictionaries selects the replacement dictionaries named by dictionary_id, or the
+// default dictionaries of the virtual key when none are named. dictionary_id takes a
+// comma-separated list and may be repeated. It writes an error response and returns
+// false when a named dictionary is not configured.
+func applyDictionaries(c *gin.Context, postProcessService services.IPostProcessService, req *transcriptionRequest) bool {
+	ids := postFormList(c, "dictionary_id")
+	for _, id := range ids {
+		if !postProcessService.HasDictionary(id) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Unknown dictionary_id: " + id,
+			})
+			return false
+		}
+	}
+
+	// Defaults of a key may name dictionaries removed from the configuration since
+	if len(ids) == 0 {
+		if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+			for _, id := range virtualKey.DictionaryIDs {
+				if !postProcessService.HasDictionary(id) {
+					logging.FromContext(c.Request.Context()).Warn("Default replacement dictionary not found", "dictionary_id", id)
+					continue
+				}
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	req.params.Dictionaries = ids
+	return true
+}
+
+// postFormList returns the values of a form field that takes a comma-separated list and
+// may be repeated
+func postFormList(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range c.PostFormArray(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
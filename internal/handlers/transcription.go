@@ -1,81 +1,544 @@
 package handlers
 
 import (
-	"log"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
 	"qwen3-compatibility/internal/middleware"
 	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
 	"qwen3-compatibility/internal/services"
+	"qwen3-compatibility/internal/tracing"
+)
+
+// Response headers naming the models and providers that served a transcription
+const (
+	servedModelHeader    = "X-ASR-Model"
+	servedProviderHeader = "X-ASR-Provider"
 )
 
 type TranscriptionHandler struct {
-	uploadService services.IUploadService
-	asrService    services.IASRService
-	config        *config.Config
+	uploadService      services.IUploadService
+	fileService        services.IFileService
+	asrService         services.IASRService
+	chunkingService    services.IChunkingService
+	diarizationService services.IDiarizationService
+	vocabularyService  services.IVocabularyService
+	postProcessService services.IPostProcessService
+	config             *config.Config
 }
 
-func NewTranscriptionHandler(uploadService services.IUploadService, asrService services.IASRService, cfg *config.Config) *TranscriptionHandler {
+// NewTranscriptionHandler creates the transcription handler. fileService may be nil when the
+// Files API is disabled, in which case requests cannot reference a file_id,
+// chunkingService may be nil when long recordings are not split, and diarizationService
+// may be nil when diarization is disabled.
+func NewTranscriptionHandler(uploadService services.IUploadService, fileService services.IFileService, asrService services.IASRService, chunkingService services.IChunkingService, diarizationService services.IDiarizationService, vocabularyService services.IVocabularyService, postProcessService services.IPostProcessService, cfg *config.Config) *TranscriptionHandler {
 	return &TranscriptionHandler{
-		uploadService: uploadService,
-		asrService:    asrService,
-		config:        cfg,
+		uploadService:      uploadService,
+		fileService:        fileService,
+		asrService:         asrService,
+		chunkingService:    chunkingService,
+		diarizationService: diarizationService,
+		vocabularyService:  vocabularyService,
+		postProcessService: postProcessService,
+		config:             cfg,
 	}
 }
 
+// transcriptionRequest holds the parsed and validated transcription form. Audio comes either
+// from the uploaded file or from a file stored through the Files API (storedFile).
+type transcriptionRequest struct {
+	file       multipart.File
+	storedFile *models.File
+	header     *multipart.FileHeader
+	apiKey     string
+	params     models.TranscriptionParams
+	// audio is the probed audio stream, set once the file is validated
+	audio *models.AudioInfo
+}
+
 // Transcription handles the /v1/audio/transcriptions endpoint
 func (h *TranscriptionHandler) Transcription(c *gin.Context) {
 	startTime := time.Now()
 
+	req, ok := bindTranscriptionRequest(c, h.fileService)
+	if !ok {
+		return
+	}
+	defer closeFormFile(c, req.file)
+
+	if !applyVocabulary(c, h.vocabularyService, req) || !applyDictionaries(c, h.postProcessService, req) {
+		return
+	}
+
+	if req.params.Diarize && h.diarizationService == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: services.ErrDiarizationDisabled.Error(),
+		})
+		return
+	}
+	if req.params.Diarize && !req.params.AllowsModel(h.diarizationService.Model()) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: services.ErrDiarizationModelNotAllowed.Error(),
+		})
+		return
+	}
+
+	if !validateRequestFile(c, h.uploadService, h.fileService, req) {
+		return
+	}
+
+	logger := logging.FromContext(c.Request.Context())
+
+	// Diarized requests are transcribed by the diarization model. Long WAV and PCM
+	// recordings are transcribed in chunks, and in utterances when segment timestamps are
+	// requested or the channels are split; other audio is uploaded as is.
+	var uploadResult *models.UploadResult
+	var asrResponse *models.ASRResponse
+	var segments []models.TranscriptionSegment
+	var channels []models.ChannelTranscript
+	var err error
+	if req.params.Diarize {
+		uploadResult, asrResponse, segments, ok = h.transcribeDiarized(c, req)
+		if !ok {
+			return
+		}
+	} else {
+		asrResponse, segments, channels, err = h.transcribeChunked(c, req)
+	}
+	switch {
+	case err == nil:
+	case stderrors.Is(err, services.ErrChunkingNotNeeded):
+		uploadResult, asrResponse, ok = h.transcribe(c, req)
+		if !ok {
+			return
+		}
+	case stderrors.Is(err, services.ErrChannelSplitUnsupported), stderrors.Is(err, services.ErrTooManyChannels):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	default:
+		logger.Error("Chunked transcription failed", "error", err, "upstream_request_id", upstreamRequestIDs(c))
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Transcription failed",
+		})
+		return
+	}
+
+	setServedHeaders(c, asrResponse.Served)
+
+	// Calculate processing time
+	processingTimeMs := time.Since(startTime).Milliseconds()
+
+	format := req.params.ResponseFormat
+	if format == models.ResponseFormatVerboseJSON {
+		response := h.asrService.CreateVerboseResponse(asrResponse, processingTimeMs, uploadResult)
+		response.Duration = responseDuration(&response.TranscriptionResponse, req.audio)
+		response.Audio = req.audio
+		response.Segments = responseSegments(&response.TranscriptionResponse, segments)
+		response.Channels = channels
+		response.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		h.postProcessService.Process(c.Request.Context(), &req.params, &response.TranscriptionResponse)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	response := h.asrService.ConvertToOpenAIFormat(asrResponse, processingTimeMs)
+	response.Duration = responseDuration(response, req.audio)
+	response.Channels = channels
+	if format.HasSegments() {
+		response.Segments = responseSegments(response, segments)
+	}
+	h.postProcessService.Process(c.Request.Context(), &req.params, response)
+
+	// Add upload info to response; chunked transcriptions have one upload per chunk
+	if uploadResult != nil {
+		response.UploadInfo = uploadResult.Info()
+	}
+	response.ProcessingTime = processingTimeMs
+
+	switch format {
+	case models.ResponseFormatText:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(response.Text))
+	case models.ResponseFormatSRT:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(formatSRT(response.Segments)))
+	case models.ResponseFormatVTT:
+		c.Data(http.StatusOK, "text/vtt; charset=utf-8", []byte(formatVTT(response.Segments)))
+	default:
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// transcribe transcribes the audio in a single request, falling back to the fallback
+// models of the requested model on upstream failures. Audio is uploaded for providers
+// reading from OSS and sent inline to the others. It writes an error response and
+// returns false on failure.
+func (h *TranscriptionHandler) transcribe(c *gin.Context, req *transcriptionRequest) (*models.UploadResult, *models.ASRResponse, bool) {
+	ctx := c.Request.Context()
+
+	// failure is the error reported for the step that failed in the last attempt
+	var failure string
+	uploadResult, asrResponse, err := services.TranscribeWithFallback(ctx, h.asrService, req.params, func(params models.TranscriptionParams) (*models.UploadResult, *models.ASRResponse, error) {
+		if !h.asrService.NeedsUpload(params.Model) {
+			failure = "Transcription failed"
+			return h.transcribeInline(ctx, req, params, &failure)
+		}
+
+		uploadResult, err := h.upload(ctx, req, params)
+		if err != nil {
+			failure = "File upload failed"
+			return nil, nil, err
+		}
+
+		// Call ASR service with prompt
+		failure = "Transcription failed"
+		asrResponse, err := h.asrService.TranscribeAudio(ctx, req.apiKey, uploadResult.OSSURL, params.Model, params.Language, params.EnableITN, params.Prompt)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uploadResult, asrResponse, nil
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error(failure, "error", err, "upstream_request_id", upstreamRequestIDs(c))
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: failure,
+		})
+		return nil, nil, false
+	}
+
+	return uploadResult, asrResponse, true
+}
+
+// transcribeInline sends the audio to a provider that takes it in the request instead of
+// reading an OSS upload. Stored files are spooled to a temporary file first, and failure
+// is set when they cannot be read.
+func (h *TranscriptionHandler) transcribeInline(ctx context.Context, req *transcriptionRequest, params models.TranscriptionParams, failure *string) (*models.UploadResult, *models.ASRResponse, error) {
+	file := req.file
+	if req.storedFile != nil {
+		content, err := h.fileService.Open(ctx, req.storedFile.ID, req.storedFile.Owner)
+		if err != nil {
+			*failure = "Failed to read file"
+			return nil, nil, fmt.Errorf("failed to open stored file %s: %w", req.storedFile.ID, err)
+		}
+		defer func() { _ = content.Close() }()
+
+		var cleanup func()
+		file, cleanup, err = services.AsMultipartFile(content)
+		if err != nil {
+			*failure = "Failed to read file"
+			return nil, nil, fmt.Errorf("failed to spool stored file %s: %w", req.storedFile.ID, err)
+		}
+		defer cleanup()
+	} else if _, err := file.Seek(0, io.SeekStart); err != nil {
+		// A failed attempt may have read part of the file
+		*failure = "Failed to read file"
+		return nil, nil, fmt.Errorf("failed to rewind file: %w", err)
+	}
+
+	return services.TranscribeFile(ctx, h.uploadService, h.asrService, req.apiKey, file, req.header, params, services.UploadOptions{
+		KeepSilence: params.KeepSilence,
+	})
+}
+
+// transcribeDiarized uploads the audio for the diarization model and transcribes it with
+// speaker labels. Silence is kept so that the segments are timed against the original
+// recording. It writes an error response and returns false on failure.
+func (h *TranscriptionHandler) transcribeDiarized(c *gin.Context, req *transcriptionRequest) (*models.UploadResult, *models.ASRResponse, []models.TranscriptionSegment, bool) {
+	logger := logging.FromContext(c.Request.Context())
+	params := req.params
+	params.Model = h.diarizationService.Model()
+	params.KeepSilence = true
+
+	uploadResult, err := h.upload(c.Request.Context(), req, params)
+	if err != nil {
+		logger.Error("File upload failed", "error", err, "upstream_request_id", upstreamRequestIDs(c))
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "File upload failed",
+		})
+		return nil, nil, nil, false
+	}
+
+	asrResponse, segments, err := h.diarizationService.Transcribe(c.Request.Context(), req.apiKey, uploadResult.OSSURL, req.params)
+	if err != nil {
+		logger.Error("Diarization failed", "error", err, "upstream_request_id", upstreamRequestIDs(c))
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Transcription failed",
+		})
+		return nil, nil, nil, false
+	}
+
+	return uploadResult, asrResponse, segments, true
+}
+
+// upload uploads the audio for params.Model. Stored files reuse their cached OSS upload
+// when possible.
+func (h *TranscriptionHandler) upload(ctx context.Context, req *transcriptionRequest, params models.TranscriptionParams) (*models.UploadResult, error) {
+	var uploadResult *models.UploadResult
+	var err error
+	if req.storedFile != nil {
+		uploadResult, err = h.fileService.UploadFile(ctx, req.apiKey, req.storedFile, params)
+	} else {
+		if _, err := req.file.Seek(0, io.SeekStart); err != nil {
+			// A failed attempt may have read part of the file
+			return nil, fmt.Errorf("failed to rewind file: %w", err)
+		}
+		uploadResult, err = h.uploadService.UploadFile(ctx, req.apiKey, req.file, req.header, params.Model, services.UploadOptions{
+			ValidityHours: 48, // 48 hours default
+			KeepSilence:   params.KeepSilence,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("File uploaded successfully", "oss_url", uploadResult.OSSURL, "expires", uploadResult.ExpireTime.Format(time.RFC3339))
+	return uploadResult, nil
+}
+
+// transcribeChunked transcribes long WAV and PCM audio in chunks, in utterances when the
+// response format has segments, or channel by channel with channel_mode=split. It returns
+// services.ErrChunkingNotNeeded when the audio is sent in a single request.
+func (h *TranscriptionHandler) transcribeChunked(c *gin.Context, req *transcriptionRequest) (*models.ASRResponse, []models.TranscriptionSegment, []models.ChannelTranscript, error) {
+	split := req.params.ChannelMode == models.ChannelModeSplit
+	if h.chunkingService == nil || !h.chunkingService.Supports(req.header) {
+		if split {
+			return nil, nil, nil, services.ErrChannelSplitUnsupported
+		}
+		return nil, nil, nil, services.ErrChunkingNotNeeded
+	}
+
+	var content io.Reader = req.file
+	if req.storedFile != nil {
+		stored, err := h.fileService.Open(c.Request.Context(), req.storedFile.ID, middleware.OwnerID(c))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		defer func() { _ = stored.Close() }()
+		content = stored
+	}
+
+	if split {
+		return h.chunkingService.TranscribeChannels(c.Request.Context(), req.apiKey, content, req.header, req.params, 48)
+	}
+	if req.params.ResponseFormat.HasSegments() {
+		asrResponse, segments, err := h.chunkingService.TranscribeSegments(c.Request.Context(), req.apiKey, content, req.header, req.params, 48)
+		return asrResponse, segments, nil, err
+	}
+	asrResponse, err := h.chunkingService.Transcribe(c.Request.Context(), req.apiKey, content, req.header, req.params, 48)
+	return asrResponse, nil, nil, err
+}
+
+// responseDuration returns the duration reported by DashScope, or the duration stated by
+// the file headers when DashScope reports none
+func responseDuration(response *models.TranscriptionResponse, audio *models.AudioInfo) float64 {
+	if response.Duration == 0 && audio != nil {
+		return audio.Duration
+	}
+	return response.Duration
+}
+
+// responseSegments returns the segments of a transcription, or a single segment covering
+// the whole transcript when the audio was not cut into utterances
+func responseSegments(response *models.TranscriptionResponse, segments []models.TranscriptionSegment) []models.TranscriptionSegment {
+	if segments != nil {
+		return segments
+	}
+	return services.WholeSegments(response)
+}
+
+// validateRequestFile checks the audio of a request against the upload limits, replaces
+// its declared content type with the sniffed one and records the probed audio stream. It
+// writes an error response and returns false on failure.
+func validateRequestFile(c *gin.Context, uploadService services.IUploadService, fileService services.IFileService, req *transcriptionRequest) bool {
+	var file io.ReaderAt = req.file
+	if req.storedFile != nil {
+		content, err := fileService.Open(c.Request.Context(), req.storedFile.ID, req.storedFile.Owner)
+		if err == nil {
+			defer func() { _ = content.Close() }()
+			var cleanup func()
+			file, cleanup, err = services.AsMultipartFile(content)
+			if err == nil {
+				defer cleanup()
+			}
+		}
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to open stored file", "file_id", req.storedFile.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to read file",
+			})
+			return false
+		}
+	}
+
+	audioInfo, err := uploadService.ValidateFile(file, req.header)
+	if err == nil && audioInfo != nil {
+		if req.params.ChannelMode == models.ChannelModeSplit && audioInfo.Channels == 1 {
+			err = services.ErrChannelSplitUnsupported
+		}
+	}
+	if err == nil && audioInfo != nil {
+		if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+			if limit := virtualKey.Limits.MaxDurationSeconds; limit > 0 && audioInfo.Duration > float64(limit) {
+				err = errors.NewDurationError(limit)
+			}
+		}
+	}
+	if err != nil {
+		if apiErr, ok := errors.IsAPIError(err); ok {
+			c.JSON(apiErr.HTTPStatus(), models.ErrorResponse{
+				Error: apiErr.Message,
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return false
+	}
+
+	req.audio = audioInfo
+	return true
+}
+
+// bindTranscriptionRequest parses and validates the transcription form shared by the
+// synchronous and job endpoints. It writes an error response and returns false on failure.
+func bindTranscriptionRequest(c *gin.Context, fileService services.IFileService) (*transcriptionRequest, bool) {
 	// Parse form data
+	_, parseSpan := tracing.Start(c.Request.Context(), "transcription.parse_multipart")
 	file, header, err := c.Request.FormFile("file")
+	if stderrors.Is(err, http.ErrMissingFile) {
+		err = nil
+	}
+	tracing.End(parseSpan, err)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: "Failed to get file from form",
 		})
-		return
+		return nil, false
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Printf("Failed to close file: %v", err)
-		}
-	}()
 
+	fileID := c.PostForm("file_id")
+	if file == nil {
+		return bindStoredFileRequest(c, fileService, fileID)
+	}
+	if fileID != "" {
+		closeFormFile(c, file)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Provide either file or file_id, not both",
+		})
+		return nil, false
+	}
+
+	req, ok := bindTranscriptionFields(c, header)
+	if !ok {
+		closeFormFile(c, file)
+		return nil, false
+	}
+	req.file = file
+
+	return req, true
+}
+
+// bindStoredFileRequest binds a transcription request whose audio was uploaded earlier
+// through the Files API
+func bindStoredFileRequest(c *gin.Context, fileService services.IFileService, fileID string) (*transcriptionRequest, bool) {
+	if fileID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Failed to get file from form",
+		})
+		return nil, false
+	}
+	if fileService == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "file_id is not supported: the Files API is disabled",
+		})
+		return nil, false
+	}
+
+	storedFile, err := fileService.Get(fileID, middleware.OwnerID(c))
+	if err != nil {
+		respondFileNotFound(c)
+		return nil, false
+	}
+
+	req, ok := bindTranscriptionFields(c, services.FileHeader(storedFile))
+	if !ok {
+		return nil, false
+	}
+	req.storedFile = storedFile
+
+	return req, true
+}
+
+// bindTranscriptionFields parses and validates the non-file transcription form fields
+func bindTranscriptionFields(c *gin.Context, header *multipart.FileHeader) (*transcriptionRequest, bool) {
 	// Extract API key from context
 	apiKey, exists := c.Get(middleware.APIKeyContextKey)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 			Error: "Missing API key in context",
 		})
-		return
+		return nil, false
 	}
 	apiKeyStr, ok := apiKey.(string)
 	if !ok {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error: "Invalid API key type",
 		})
-		return
+		return nil, false
 	}
 
 	// Parse other form fields
 	model := c.PostForm("model")
 	language := c.PostForm("language")
 	prompt := c.PostForm("prompt") // Optional: contextual information for transcription
+	responseFormat := c.DefaultPostForm("response_format", string(models.ResponseFormatJSON))
 
 	// Validate model is provided
 	if model == "" {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: "model parameter is required",
 		})
-		return
+		return nil, false
 	}
+	middleware.WithLogAttrs(c, "model", model)
+	logger := logging.FromContext(c.Request.Context())
 
-	log.Printf("Transcription request: file=%s, model=%s, language=%s, prompt=%s, size=%d",
-		header.Filename, model, language, prompt, header.Size)
+	// Enforce virtual key restrictions
+	if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+		if !virtualKey.AllowsModel(model) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error: "Model is not allowed for this API key",
+			})
+			return nil, false
+		}
+		if virtualKey.Limits.MaxFileSize > 0 && header.Size > virtualKey.Limits.MaxFileSize {
+			apiErr := errors.NewFileSizeError(virtualKey.Limits.MaxFileSize)
+			c.JSON(apiErr.HTTPStatus(), models.ErrorResponse{
+				Error: apiErr.Message,
+			})
+			return nil, false
+		}
+	}
+	// Requests are labeled with the model only once the key allows it
+	c.Set(middleware.ModelContextKey, model)
+
+	logger.Info("Transcription request",
+		"file", header.Filename, "language", language, "prompt", prompt, "size", header.Size)
 
 	// Validate language if provided
 	var languagePtr *models.SupportedLanguage
@@ -83,50 +546,212 @@ func (h *TranscriptionHandler) Transcription(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: "Unsupported language",
 		})
-		return
+		return nil, false
 	}
 	if language != "" {
 		lang := models.SupportedLanguage(language)
 		languagePtr = &lang
 	}
 
-	// Upload file
-	uploadResult, err := h.uploadService.UploadFile(c.Request.Context(), apiKeyStr, file, header, model, 48) // 48 hours default
-	if err != nil {
-		log.Printf("File upload failed: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: "File upload failed",
+	if !models.IsValidResponseFormat(responseFormat) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "response_format must be one of json, text, verbose_json, srt or vtt",
 		})
-		return
+		return nil, false
+	}
+	for _, granularity := range c.PostFormArray("timestamp_granularities[]") {
+		if granularity != "segment" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Only segment timestamp granularity is supported",
+			})
+			return nil, false
+		}
+		if responseFormat != string(models.ResponseFormatVerboseJSON) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "timestamp_granularities requires response_format verbose_json",
+			})
+			return nil, false
+		}
 	}
 
-	log.Printf("File uploaded successfully: %s, expires: %s", uploadResult.OSSURL, uploadResult.ExpireTime.Format(time.RFC3339))
+	keepSilence := false
+	if raw := c.PostForm("keep_silence"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "keep_silence must be true or false",
+			})
+			return nil, false
+		}
+		keepSilence = parsed
+	}
 
-	// Call ASR service with prompt
-	asrResponse, err := h.asrService.TranscribeAudio(c.Request.Context(), apiKeyStr, uploadResult.OSSURL, model, languagePtr, prompt)
-	if err != nil {
-		log.Printf("ASR service failed: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error: "Transcription failed",
+	// enable_itn overrides dashscope.enable_itn when set
+	var enableITN *bool
+	if raw := c.PostForm("enable_itn"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "enable_itn must be true or false",
+			})
+			return nil, false
+		}
+		enableITN = &parsed
+	}
+
+	channelMode := c.DefaultPostForm("channel_mode", string(models.ChannelModeMixed))
+	if !models.IsValidChannelMode(channelMode) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "channel_mode must be mixed or split",
 		})
-		return
+		return nil, false
 	}
 
-	// Calculate processing time
-	processingTimeMs := time.Since(startTime).Milliseconds()
+	diarize := false
+	if raw := c.PostForm("diarize"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "diarize must be true or false",
+			})
+			return nil, false
+		}
+		diarize = parsed
+	}
+	if diarize && models.ChannelMode(channelMode) == models.ChannelModeSplit {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "diarize cannot be combined with channel_mode=split",
+		})
+		return nil, false
+	}
 
-	// Always return JSON format
-	response := h.asrService.ConvertToOpenAIFormat(asrResponse, processingTimeMs)
+	speakerCount := 0
+	if raw := c.PostForm("speaker_count"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 2 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "speaker_count must be an integer between 2 and 100",
+			})
+			return nil, false
+		}
+		if !diarize {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "speaker_count requires diarize=true",
+			})
+			return nil, false
+		}
+		speakerCount = parsed
+	}
 
-	// Add upload info to response
-	uploadInfo := &models.UploadInfo{
-		OSSURL:     uploadResult.OSSURL,
-		ExpireTime: uploadResult.ExpireTime.Format(time.RFC3339),
-		ModelUsed:  uploadResult.ModelUsed,
+	outputScript := c.PostForm("output_script")
+	if outputScript != "" {
+		if !models.IsValidOutputScript(outputScript) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "output_script must be hant or hans",
+			})
+			return nil, false
+		}
+		if languagePtr != nil && !languagePtr.IsChinese() {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "output_script requires language zh or yue",
+			})
+			return nil, false
+		}
 	}
 
-	response.UploadInfo = uploadInfo
-	response.ProcessingTime = processingTimeMs
+	var redact []models.RedactionCategory
+	for _, category := range postFormList(c, "redact") {
+		switch {
+		case category == "all":
+			redact = slices.Clone(models.RedactionCategories)
+		case models.IsValidRedactionCategory(category):
+			if !slices.Contains(redact, models.RedactionCategory(category)) {
+				redact = append(redact, models.RedactionCategory(category))
+			}
+		default:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "redact must be all or a list of profanity, phone, email, id_number and credit_card",
+			})
+			return nil, false
+		}
+	}
+
+	var include []models.ResponseInclude
+	for _, value := range postFormList(c, "include[]") {
+		if !models.IsValidResponseInclude(value) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "include[] must be emotion or annotations",
+			})
+			return nil, false
+		}
+		if responseFormat != string(models.ResponseFormatJSON) && responseFormat != string(models.ResponseFormatVerboseJSON) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "include[] requires response_format json or verbose_json",
+			})
+			return nil, false
+		}
+		if !slices.Contains(include, models.ResponseInclude(value)) {
+			include = append(include, models.ResponseInclude(value))
+		}
+	}
 
-	c.JSON(http.StatusOK, response)
+	var allowedModels []string
+	if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+		allowedModels = virtualKey.AllowedModels
+	}
+
+	return &transcriptionRequest{
+		header: header,
+		apiKey: apiKeyStr,
+		params: models.TranscriptionParams{
+			Model:          model,
+			Language:       languagePtr,
+			Prompt:         prompt,
+			ResponseFormat: models.ResponseFormat(responseFormat),
+			KeepSilence:    keepSilence,
+			EnableITN:      enableITN,
+			ChannelMode:    models.ChannelMode(channelMode),
+			Diarize:        diarize,
+			OutputScript:   models.OutputScript(outputScript),
+			Redact:         redact,
+			Include:        include,
+			SpeakerCount:   speakerCount,
+			AllowedModels:  allowedModels,
+		},
+	}, true
+}
+
+func closeFormFile(c *gin.Context, file multipart.File) {
+	if file == nil {
+		return
+	}
+	if err := file.Close(); err != nil {
+		logging.FromContext(c.Request.Context()).Warn("Failed to close file", "error", err)
+	}
+}
+
+// setServedHeaders names the models and providers that served the transcription, as
+// comma-separated lists when chunks were served by different fallbacks
+func setServedHeaders(c *gin.Context, served []models.ServedBy) {
+	var modelNames, providers []string
+	for _, s := range served {
+		if !slices.Contains(modelNames, s.Model) {
+			modelNames = append(modelNames, s.Model)
+		}
+		if !slices.Contains(providers, s.Provider) {
+			providers = append(providers, s.Provider)
+		}
+	}
+	if len(modelNames) > 0 {
+		c.Header(servedModelHeader, strings.Join(modelNames, ", "))
+		c.Header(servedProviderHeader, strings.Join(providers, ", "))
+	}
+}
+
+// upstreamRequestIDs returns the DashScope request IDs recorded for the current request
+func upstreamRequestIDs(c *gin.Context) string {
+	if upstream := requestid.UpstreamFromContext(c.Request.Context()); upstream != nil {
+		return upstream.String()
+	}
+	return ""
 }
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/middleware"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/services"
+	"qwen3-compatibility/internal/store"
+)
+
+const vocabularyIDError = "Vocabulary IDs must be 1 to 64 lowercase letters, digits, '-' or '_'"
+
+type VocabulariesHandler struct {
+	vocabularyService services.IVocabularyService
+}
+
+func NewVocabulariesHandler(vocabularyService services.IVocabularyService) *VocabulariesHandler {
+	return &VocabulariesHandler{
+		vocabularyService: vocabularyService,
+	}
+}
+
+// ListVocabularies handles GET /admin/vocabularies
+func (h *VocabulariesHandler) ListVocabularies(c *gin.Context) {
+	vocabularies := h.vocabularyService.List()
+
+	response := models.VocabularyListResponse{
+		Object: "list",
+		Data:   make([]models.VocabularyResponse, 0, len(vocabularies)),
+	}
+	for i := range vocabularies {
+		response.Data = append(response.Data, toVocabularyResponse(&vocabularies[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetVocabulary handles GET /admin/vocabularies/:id
+func (h *VocabulariesHandler) GetVocabulary(c *gin.Context) {
+	vocabulary, err := h.vocabularyService.Get(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toVocabularyResponse(vocabulary))
+}
+
+// PutVocabulary handles PUT /admin/vocabularies/:id, creating the vocabulary or
+// replacing its terms
+func (h *VocabulariesHandler) PutVocabulary(c *gin.Context) {
+	id := c.Param("id")
+	if !models.IsValidVocabularyID(id) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: vocabularyIDError,
+		})
+		return
+	}
+
+	var req models.PutVocabularyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: terms is required",
+		})
+		return
+	}
+
+	vocabulary, err := h.vocabularyService.Put(id, req.Terms)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("Vocabulary saved", "vocabulary_id", id, "terms", len(vocabulary.Terms))
+	c.JSON(http.StatusOK, toVocabularyResponse(vocabulary))
+}
+
+// DeleteVocabulary handles DELETE /admin/vocabularies/:id
+func (h *VocabulariesHandler) DeleteVocabulary(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.vocabularyService.Delete(id); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("Vocabulary deleted", "vocabulary_id", id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "vocabulary",
+		"deleted": true,
+	})
+}
+
+func (h *VocabulariesHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, store.ErrVocabularyNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Vocabulary not found",
+		})
+	case stderrors.Is(err, services.ErrVocabularyReadOnly):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: "Vocabulary is defined in the configuration and cannot be changed",
+		})
+	case stderrors.Is(err, services.ErrVocabularyStoreDisabled):
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: "Vocabulary store is not configured",
+		})
+	default:
+		if apiErr, ok := errors.IsAPIError(err); ok {
+			c.JSON(apiErr.HTTPStatus(), models.ErrorResponse{
+				Error: apiErr.Message,
+			})
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("Vocabulary store operation failed", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Vocabulary store operation failed",
+		})
+	}
+}
+
+func toVocabularyResponse(vocabulary *models.Vocabulary) models.VocabularyResponse {
+	response := models.VocabularyResponse{
+		ID:     vocabulary.ID,
+		Object: "vocabulary",
+		Terms:  vocabulary.Terms,
+		Source: vocabulary.Source,
+	}
+	if response.Terms == nil {
+		response.Terms = []string{}
+	}
+	if !vocabulary.CreatedAt.IsZero() {
+		response.CreatedAt = vocabulary.CreatedAt.Unix()
+		response.UpdatedAt = vocabulary.UpdatedAt.Unix()
+	}
+	return response
+}
+
+func validVocabularyIDs(ids []string) bool {
+	for _, id := range ids {
+		if !models.IsValidVocabularyID(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyVocabulary merges the vocabularies named by vocabulary_id, or the default
+// vocabularies of the virtual key when none are named, into the prompt of the request.
+// vocabulary_id takes a comma-separated list and may be repeated. It writes an error
+// response and returns false on failure.
+func applyVocabulary(c *gin.Context, vocabularyService services.IVocabularyService, req *transcriptionRequest) bool {
+	ids := postFormList(c, "vocabulary_id")
+
+	skipUnknown := false
+	if len(ids) == 0 {
+		if virtualKey := middleware.GetVirtualKey(c); virtualKey != nil {
+			ids = virtualKey.VocabularyIDs
+			skipUnknown = true
+		}
+	}
+	if len(ids) == 0 || vocabularyService == nil {
+		return true
+	}
+
+	prompt, err := vocabularyService.Prompt(c.Request.Context(), req.params.Prompt, ids, skipUnknown)
+	if apiErr, ok := errors.IsAPIError(err); ok {
+		c.JSON(apiErr.HTTPStatus(), models.ErrorResponse{
+			Error: apiErr.Message,
+		})
+		return false
+	}
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to load vocabularies", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to load vocabularies",
+		})
+		return false
+	}
+
+	req.params.Prompt = prompt
+	return true
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/services"
+)
+
+type WebhooksHandler struct {
+	webhookService services.IWebhookService
+}
+
+func NewWebhooksHandler(webhookService services.IWebhookService) *WebhooksHandler {
+	return &WebhooksHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ListDeadLetters handles GET /admin/webhooks/dead-letters
+func (h *WebhooksHandler) ListDeadLetters(c *gin.Context) {
+	entries := h.webhookService.ListDeadLetters()
+
+	response := models.WebhookDeadLetterListResponse{
+		Object: "list",
+		Data:   make([]models.WebhookDeadLetterResponse, 0, len(entries)),
+	}
+	for i := range entries {
+		response.Data = append(response.Data, toDeadLetterResponse(&entries[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Redeliver handles POST /admin/webhooks/dead-letters/:id/redeliver
+func (h *WebhooksHandler) Redeliver(c *gin.Context) {
+	id := c.Param("id")
+
+	entry, err := h.webhookService.Redeliver(c.Request.Context(), id)
+	if errors.Is(err, services.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: "Dead letter not found",
+		})
+		return
+	}
+	if entry == nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to redeliver webhook", "delivery_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to redeliver webhook",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":       "Webhook redelivery failed",
+			"dead_letter": toDeadLetterResponse(entry),
+		})
+		return
+	}
+
+	logging.FromContext(c.Request.Context()).Info("Webhook redelivered", "delivery_id", id, "job_id", entry.JobID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":        entry.ID,
+		"object":    "webhook.delivery",
+		"job_id":    entry.JobID,
+		"delivered": true,
+	})
+}
+
+func toDeadLetterResponse(entry *models.WebhookDeadLetter) models.WebhookDeadLetterResponse {
+	return models.WebhookDeadLetterResponse{
+		ID:             entry.ID,
+		Object:         "webhook.dead_letter",
+		JobID:          entry.JobID,
+		URL:            entry.URL,
+		Payload:        entry.Payload,
+		Attempts:       entry.Attempts,
+		LastStatusCode: entry.LastStatusCode,
+		LastError:      entry.LastError,
+		CreatedAt:      entry.CreatedAt.Unix(),
+		FailedAt:       entry.FailedAt.Unix(),
+	}
+}
+
+// validCallbackURL reports whether raw is an absolute http or https URL
+func validCallbackURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Attribute keys whose values are secrets and are always replaced
+var secretKeys = map[string]bool{
+	"api_key":       true,
+	"apikey":        true,
+	"authorization": true,
+	"upstream_key":  true,
+	"secret":        true,
+	"token":         true,
+	"admin_token":   true,
+	"signature":     true,
+}
+
+// Attribute keys carrying customer content; only the length is kept
+var contentKeys = map[string]bool{
+	"prompt":  true,
+	"context": true,
+	"text":    true,
+}
+
+// Attribute keys carrying storage URLs; only the scheme and host are kept
+var urlKeys = map[string]bool{
+	"oss_url":   true,
+	"audio_url": true,
+	"file_url":  true,
+}
+
+// Patterns scrubbed from free-form strings such as messages and errors
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{8,}`),
+	regexp.MustCompile(`oss://[^\s"'<>]+`),
+	regexp.MustCompile(`(?i)("(?:signature|oss_access_key_id|policy)"\s*:\s*")[^"]*`),
+	regexp.MustCompile(`(?i)\b((?:signature|ossaccesskeyid|policy)=)[^\s&"]+`),
+}
+
+// RedactingHandler removes API keys, prompts and OSS URLs from log records before
+// passing them to the wrapped handler
+type RedactingHandler struct {
+	next slog.Handler
+}
+
+func NewRedactingHandler(next slog.Handler) *RedactingHandler {
+	return &RedactingHandler{next: next}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redactedRecord := slog.NewRecord(record.Time, record.Level, RedactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redactedRecord.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redactedRecord)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = redactAttr(attr)
+	}
+	return &RedactingHandler{next: h.next.WithAttrs(redactedAttrs)}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name)}
+}
+
+// RedactString scrubs bearer tokens, API keys, OSS URLs and upload signatures from s
+func RedactString(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if sub := pattern.FindStringSubmatch(match); len(sub) == 2 {
+				return sub[1] + redacted
+			}
+			if strings.HasPrefix(match, "oss://") {
+				return "oss://" + redacted
+			}
+			return redacted
+		})
+	}
+	return s
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		redactedGroup := make([]any, len(group))
+		for i, member := range group {
+			redactedGroup[i] = redactAttr(member)
+		}
+		return slog.Group(attr.Key, redactedGroup...)
+	}
+
+	switch {
+	case secretKeys[key]:
+		return slog.String(attr.Key, redacted)
+	case contentKeys[key]:
+		return slog.String(attr.Key, fmt.Sprintf("[REDACTED len=%d]", len(value.String())))
+	case urlKeys[key]:
+		return slog.String(attr.Key, redactURL(value.String()))
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, RedactString(err.Error()))
+		}
+	}
+
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// redactURL keeps the scheme and host of a URL and drops the path and query
+func redactURL(url string) string {
+	if url == "" {
+		return ""
+	}
+	scheme, rest, found := strings.Cut(url, "://")
+	if !found {
+		return redacted
+	}
+	if scheme == "oss" {
+		return "oss://" + redacted
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host + "/" + redacted
+}
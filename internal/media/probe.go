@@ -0,0 +1,462 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"qwen3-compatibility/internal/audio"
+)
+
+var ErrProbeUnsupported = stderrors.New("audio format cannot be probed")
+
+const (
+	// Bytes searched for the first MPEG audio frame after any ID3 tag
+	mpegSearchLen = 64 * 1024
+	// Bytes at the end of an Ogg stream searched for the last page
+	oggTailLen = 64 * 1024
+	// Opus granule positions always count 48 kHz samples
+	opusGranuleRate = 48000
+)
+
+// Info describes an audio stream as stated by its container headers
+type Info struct {
+	Duration   time.Duration
+	SampleRate int
+	Channels   int
+	// Bitrate in bits per second, averaged over the file when the headers do not state it
+	Bitrate int
+}
+
+// Probe reads the headers of a WAV, MP3, FLAC, Ogg (Opus or Vorbis) or MP4/M4A file of
+// size bytes stored at r. contentType selects the parser, usually the type returned by
+// Sniff. Other formats return ErrProbeUnsupported. Only headers and, for Ogg, the last
+// page are read.
+func Probe(r io.ReaderAt, size int64, contentType string) (*Info, error) {
+	var info *Info
+	var err error
+	switch families[contentType] {
+	case "wav":
+		info, err = probeWAV(r, size)
+	case "mpeg":
+		info, err = probeMPEG(r, size)
+	case "flac":
+		info, err = probeFLAC(r, size)
+	case "ogg":
+		info, err = probeOgg(r, size)
+	case "mp4":
+		info, err = probeMP4(r, size)
+	default:
+		return nil, ErrProbeUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Corrupt or crafted headers may state counts whose duration does not fit
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("%w: invalid duration", ErrProbeUnsupported)
+	}
+
+	if info.Bitrate == 0 {
+		info.Bitrate = int(float64(size*8) / info.Duration.Seconds())
+	}
+	return info, nil
+}
+
+func probeWAV(r io.ReaderAt, size int64) (*Info, error) {
+	clip, err := audio.OpenWAV(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{
+		Duration:   clip.Duration(),
+		SampleRate: clip.Format.SampleRate,
+		Channels:   clip.Format.Channels,
+		Bitrate:    clip.Format.ByteRate() * 8,
+	}, nil
+}
+
+// id3Size returns the size of the ID3v2 tag at the start of the file, or 0 without a tag
+func id3Size(r io.ReaderAt) int64 {
+	var head [10]byte
+	if _, err := r.ReadAt(head[:], 0); err != nil || string(head[0:3]) != "ID3" {
+		return 0
+	}
+	size := int64(head[6]&0x7F)<<21 | int64(head[7]&0x7F)<<14 | int64(head[8]&0x7F)<<7 | int64(head[9]&0x7F)
+	if head[5]&0x10 != 0 {
+		size += 10
+	}
+	return 10 + size
+}
+
+// Bitrates in kbps by MPEG version (1 or 2, which includes 2.5), layer and index
+var (
+	mpeg1Bitrates = [3][15]int{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	}
+	mpeg2Bitrates = [3][15]int{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+	// Sample rates by version bits (2.5, reserved, 2, 1) and index
+	mpegSampleRates = [4][3]int{
+		{11025, 12000, 8000},
+		{},
+		{22050, 24000, 16000},
+		{44100, 48000, 32000},
+	}
+)
+
+// probeMPEG reads the first MPEG audio frame. The duration comes from a Xing, Info or VBRI
+// header when present, and is otherwise computed from the size at the frame's bitrate.
+func probeMPEG(r io.ReaderAt, size int64) (*Info, error) {
+	start := id3Size(r)
+	buf := make([]byte, min(mpegSearchLen, max(0, size-start)))
+	n, err := r.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read MPEG audio: %w", err)
+	}
+	buf = buf[:n]
+
+	offset := -1
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] == 0xFF && isMPEGAudioFrame(binary.BigEndian.Uint32(buf[i:])) {
+			offset = i
+			break
+		}
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: no MPEG audio frame found", ErrProbeUnsupported)
+	}
+
+	frame := buf[offset:]
+	header := binary.BigEndian.Uint32(frame)
+	versionBits := (header >> 19) & 0x3
+	layer := 3 - int((header>>17)&0x3) // 0 for Layer I, 2 for Layer III
+	bitrateIndex := (header >> 12) & 0xF
+	sampleRate := mpegSampleRates[versionBits][(header>>10)&0x3]
+	mono := (header>>6)&0x3 == 3
+	mpeg1 := versionBits == 3
+
+	bitrate := mpeg2Bitrates[layer][bitrateIndex] * 1000
+	if mpeg1 {
+		bitrate = mpeg1Bitrates[layer][bitrateIndex] * 1000
+	}
+	samplesPerFrame := 1152
+	switch {
+	case layer == 0:
+		samplesPerFrame = 384
+	case layer == 2 && !mpeg1:
+		samplesPerFrame = 576
+	}
+
+	channels := 2
+	if mono {
+		channels = 1
+	}
+	info := &Info{SampleRate: sampleRate, Channels: channels}
+
+	if frames := mpegFrameCount(frame, mpeg1, mono); frames > 0 {
+		info.Duration = seconds(float64(frames) * float64(samplesPerFrame) / float64(sampleRate))
+		return info, nil
+	}
+
+	if bitrate == 0 {
+		return nil, fmt.Errorf("%w: free format MPEG audio", ErrProbeUnsupported)
+	}
+	info.Bitrate = bitrate
+	audioBytes := size - start - int64(offset)
+	info.Duration = seconds(float64(audioBytes*8) / float64(bitrate))
+	return info, nil
+}
+
+// mpegFrameCount returns the number of frames stated by the Xing, Info or VBRI header in
+// the first frame, or 0 when there is none
+func mpegFrameCount(frame []byte, mpeg1, mono bool) int64 {
+	// The Xing header follows the side information, whose size depends on the version and
+	// the channel mode
+	sideInfo := 32
+	switch {
+	case mpeg1 && mono:
+		sideInfo = 17
+	case !mpeg1 && !mono:
+		sideInfo = 17
+	case !mpeg1 && mono:
+		sideInfo = 9
+	}
+	if xing := 4 + sideInfo; len(frame) >= xing+12 {
+		tag := string(frame[xing : xing+4])
+		flags := binary.BigEndian.Uint32(frame[xing+4:])
+		if (tag == "Xing" || tag == "Info") && flags&0x1 != 0 {
+			return int64(binary.BigEndian.Uint32(frame[xing+8:]))
+		}
+	}
+
+	if vbri := 4 + 32; len(frame) >= vbri+18 && string(frame[vbri:vbri+4]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(frame[vbri+14:]))
+	}
+	return 0
+}
+
+// probeFLAC reads the STREAMINFO block, which must be the first metadata block
+func probeFLAC(r io.ReaderAt, size int64) (*Info, error) {
+	var buf [4 + 4 + 34]byte
+	if _, err := r.ReadAt(buf[:], id3Size(r)); err != nil {
+		return nil, fmt.Errorf("failed to read FLAC header: %w", err)
+	}
+	if string(buf[0:4]) != "fLaC" || buf[4]&0x7F != 0 {
+		return nil, fmt.Errorf("%w: missing FLAC STREAMINFO", ErrProbeUnsupported)
+	}
+
+	info := buf[8:]
+	sampleRate := int(info[10])<<12 | int(info[11])<<4 | int(info[12])>>4
+	channels := int(info[12]>>1&0x7) + 1
+	totalSamples := int64(info[13]&0xF)<<32 | int64(binary.BigEndian.Uint32(info[14:18]))
+	if sampleRate == 0 {
+		return nil, fmt.Errorf("%w: invalid FLAC sample rate", ErrProbeUnsupported)
+	}
+
+	return &Info{
+		Duration:   seconds(float64(totalSamples) / float64(sampleRate)),
+		SampleRate: sampleRate,
+		Channels:   channels,
+	}, nil
+}
+
+// probeOgg reads the identification header of the first logical stream and the granule
+// position of the last page
+func probeOgg(r io.ReaderAt, size int64) (*Info, error) {
+	page := make([]byte, min(size, 27+255+64))
+	n, err := r.ReadAt(page, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read Ogg page: %w", err)
+	}
+	page = page[:n]
+	if len(page) < 27 || string(page[0:4]) != "OggS" {
+		return nil, fmt.Errorf("%w: missing Ogg page", ErrProbeUnsupported)
+	}
+	// The segment table follows the header, then the first packet
+	if len(page) < 27+int(page[26]) {
+		return nil, fmt.Errorf("%w: truncated Ogg page", ErrProbeUnsupported)
+	}
+	packet := page[27+int(page[26]):]
+
+	var info Info
+	var granuleRate int
+	var preSkip int64
+	switch {
+	case len(packet) >= 19 && string(packet[0:8]) == "OpusHead":
+		info.Channels = int(packet[9])
+		preSkip = int64(binary.LittleEndian.Uint16(packet[10:12]))
+		info.SampleRate = int(binary.LittleEndian.Uint32(packet[12:16]))
+		if info.SampleRate == 0 {
+			info.SampleRate = opusGranuleRate
+		}
+		granuleRate = opusGranuleRate
+	case len(packet) >= 30 && string(packet[0:7]) == "\x01vorbis":
+		info.Channels = int(packet[11])
+		info.SampleRate = int(binary.LittleEndian.Uint32(packet[12:16]))
+		info.Bitrate = int(int32(binary.LittleEndian.Uint32(packet[20:24])))
+		granuleRate = info.SampleRate
+	default:
+		return nil, fmt.Errorf("%w: Ogg stream is neither Opus nor Vorbis", ErrProbeUnsupported)
+	}
+	if granuleRate == 0 {
+		return nil, fmt.Errorf("%w: invalid Ogg sample rate", ErrProbeUnsupported)
+	}
+	info.Bitrate = max(0, info.Bitrate)
+
+	tailStart := max(0, size-oggTailLen)
+	tail := make([]byte, size-tailStart)
+	if _, err := r.ReadAt(tail, tailStart); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read Ogg page: %w", err)
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+14 > len(tail) || tail[i+4] != 0 {
+			continue
+		}
+		granule := int64(binary.LittleEndian.Uint64(tail[i+6 : i+14]))
+		if granule < 0 {
+			// Pages without a completed packet have granule position -1
+			continue
+		}
+		info.Duration = seconds(float64(granule-preSkip) / float64(granuleRate))
+		return &info, nil
+	}
+
+	return nil, fmt.Errorf("%w: no Ogg granule position found", ErrProbeUnsupported)
+}
+
+// probeMP4 reads the movie header for the duration and the first sound track for the
+// sample rate and channels
+func probeMP4(r io.ReaderAt, size int64) (*Info, error) {
+	moov, ok, err := findBox(r, 0, size, "moov")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: missing MP4 moov box", ErrProbeUnsupported)
+	}
+
+	mvhd, ok, err := findBox(r, moov.body, moov.end, "mvhd")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: missing MP4 mvhd box", ErrProbeUnsupported)
+	}
+	timescale, duration, err := readMediaDuration(r, mvhd)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Duration: seconds(float64(duration) / float64(timescale))}
+	if err := readSoundTrack(r, moov, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// seconds converts a duration in seconds, or returns 0 when it is not positive or does
+// not fit a time.Duration
+func seconds(s float64) time.Duration {
+	if !(s > 0) || s >= math.MaxInt64/float64(time.Second) {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// mp4Box is the extent of an MP4 box: its body starts at body and the box ends at end
+type mp4Box struct {
+	body, end int64
+}
+
+// findBox returns the first box of type typ among the boxes in [offset, end)
+func findBox(r io.ReaderAt, offset, end int64, typ string) (mp4Box, bool, error) {
+	for offset+8 <= end {
+		var header [16]byte
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return mp4Box{}, false, fmt.Errorf("failed to read MP4 box: %w", err)
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[0:4]))
+		body := offset + 8
+		switch boxSize {
+		case 0:
+			// The last box extends to the end of the file
+			boxSize = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return mp4Box{}, false, fmt.Errorf("failed to read MP4 box: %w", err)
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			body += 8
+		}
+		if boxSize < body-offset || boxSize > end-offset {
+			return mp4Box{}, false, fmt.Errorf("%w: invalid MP4 box size", ErrProbeUnsupported)
+		}
+
+		if string(header[4:8]) == typ {
+			return mp4Box{body: body, end: offset + boxSize}, true, nil
+		}
+		offset += boxSize
+	}
+	return mp4Box{}, false, nil
+}
+
+// findPath returns the box at the end of a path of nested box types
+func findPath(r io.ReaderAt, parent mp4Box, path ...string) (mp4Box, bool, error) {
+	box := parent
+	for _, typ := range path {
+		child, ok, err := findBox(r, box.body, box.end, typ)
+		if err != nil || !ok {
+			return mp4Box{}, false, err
+		}
+		box = child
+	}
+	return box, true, nil
+}
+
+// readMediaDuration reads the timescale and duration of an mvhd or mdhd box
+func readMediaDuration(r io.ReaderAt, box mp4Box) (uint32, uint64, error) {
+	var buf [32]byte
+	n, err := r.ReadAt(buf[:min(int64(len(buf)), box.end-box.body)], box.body)
+	if err != nil && err != io.EOF {
+		return 0, 0, fmt.Errorf("failed to read MP4 header: %w", err)
+	}
+
+	var timescale uint32
+	var duration uint64
+	switch {
+	case n >= 20 && buf[0] == 0:
+		timescale = binary.BigEndian.Uint32(buf[12:16])
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	case n >= 32 && buf[0] == 1:
+		timescale = binary.BigEndian.Uint32(buf[20:24])
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	default:
+		return 0, 0, fmt.Errorf("%w: invalid MP4 media header", ErrProbeUnsupported)
+	}
+	if timescale == 0 {
+		return 0, 0, fmt.Errorf("%w: invalid MP4 timescale", ErrProbeUnsupported)
+	}
+	return timescale, duration, nil
+}
+
+// readSoundTrack fills the sample rate and channels from the first sound track. Files
+// without a sound track are left as is.
+func readSoundTrack(r io.ReaderAt, moov mp4Box, info *Info) error {
+	offset := moov.body
+	for {
+		trak, ok, err := findBox(r, offset, moov.end, "trak")
+		if err != nil || !ok {
+			return err
+		}
+		offset = trak.end
+
+		hdlr, ok, err := findPath(r, trak, "mdia", "hdlr")
+		if err != nil {
+			return err
+		}
+		var handler [12]byte
+		if !ok || hdlr.end-hdlr.body < 12 {
+			continue
+		}
+		if _, err := r.ReadAt(handler[:], hdlr.body); err != nil {
+			return fmt.Errorf("failed to read MP4 handler: %w", err)
+		}
+		if string(handler[8:12]) != "soun" {
+			continue
+		}
+
+		stsd, ok, err := findPath(r, trak, "mdia", "minf", "stbl", "stsd")
+		if err != nil || !ok {
+			return err
+		}
+		// Full box header and entry count, then the first sample entry
+		var entry [8 + 36]byte
+		if stsd.end-stsd.body < int64(len(entry)) {
+			return nil
+		}
+		if _, err := r.ReadAt(entry[:], stsd.body); err != nil {
+			return fmt.Errorf("failed to read MP4 sample description: %w", err)
+		}
+		sample := entry[8:]
+		info.Channels = int(binary.BigEndian.Uint16(sample[24:26]))
+		info.SampleRate = int(binary.BigEndian.Uint32(sample[32:36]) >> 16)
+
+		// Rates above 65535 Hz do not fit the 16.16 field; the track timescale is the rate
+		if mdhd, ok, err := findPath(r, trak, "mdia", "mdhd"); err == nil && ok {
+			if timescale, _, err := readMediaDuration(r, mdhd); err == nil && (info.SampleRate == 0 || timescale > 65535) {
+				info.SampleRate = int(timescale)
+			}
+		}
+		return nil
+	}
+}
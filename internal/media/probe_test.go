@@ -0,0 +1,297 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	stderrors "errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"qwen3-compatibility/internal/audio"
+)
+
+// wavFile returns a WAV file of frames silent 16-bit frames
+func wavFile(t *testing.T, sampleRate, channels int, frames int64) []byte {
+	t.Helper()
+	format := audio.Format{Encoding: audio.EncodingPCM, Channels: channels, SampleRate: sampleRate, BitsPerSample: 16}
+	size := frames * int64(format.FrameSize())
+	clip, err := audio.NewClip(bytes.NewReader(make([]byte, size)), size, format)
+	if err != nil {
+		t.Fatalf("NewClip: %v", err)
+	}
+	data, err := io.ReadAll(clip.WAV())
+	if err != nil {
+		t.Fatalf("read WAV: %v", err)
+	}
+	return data
+}
+
+// mp3File returns an MPEG-1 Layer III stream at 128 kbps and 44.1 kHz. A Xing header
+// stating frames is added when frames is positive.
+func mp3File(frames uint32, size int) []byte {
+	data := make([]byte, size)
+	binary.BigEndian.PutUint32(data, 0xFFFB9064)
+	if frames > 0 {
+		// Joint stereo MPEG-1 has 32 bytes of side information
+		copy(data[36:], "Xing")
+		binary.BigEndian.PutUint32(data[40:], 0x1)
+		binary.BigEndian.PutUint32(data[44:], frames)
+	}
+	return data
+}
+
+// flacFile returns the STREAMINFO block of a FLAC stream
+func flacFile(sampleRate, channels int, totalSamples int64) []byte {
+	data := make([]byte, 4+4+34)
+	copy(data, "fLaC")
+	data[4] = 0x80 // Last metadata block, type STREAMINFO
+	data[7] = 34
+	info := data[8:]
+	info[10] = byte(sampleRate >> 12)
+	info[11] = byte(sampleRate >> 4)
+	info[12] = byte(sampleRate<<4) | byte(channels-1)<<1
+	info[13] = 0xF0 | byte(totalSamples>>32)&0xF
+	binary.BigEndian.PutUint32(info[14:18], uint32(totalSamples))
+	return data
+}
+
+// oggPage returns an Ogg page holding a single packet
+func oggPage(granule int64, packet []byte) []byte {
+	page := make([]byte, 27, 28+len(packet))
+	copy(page, "OggS")
+	binary.LittleEndian.PutUint64(page[6:14], uint64(granule))
+	page[26] = 1
+	page = append(page, byte(len(packet)))
+	return append(page, packet...)
+}
+
+func opusHead(channels int, preSkip uint16, sampleRate uint32) []byte {
+	packet := make([]byte, 19)
+	copy(packet, "OpusHead")
+	packet[8] = 1
+	packet[9] = byte(channels)
+	binary.LittleEndian.PutUint16(packet[10:12], preSkip)
+	binary.LittleEndian.PutUint32(packet[12:16], sampleRate)
+	return packet
+}
+
+func vorbisHead(channels int, sampleRate uint32, bitrate int32) []byte {
+	packet := make([]byte, 30)
+	copy(packet, "\x01vorbis")
+	packet[11] = byte(channels)
+	binary.LittleEndian.PutUint32(packet[12:16], sampleRate)
+	binary.LittleEndian.PutUint32(packet[20:24], uint32(bitrate))
+	return packet
+}
+
+func boxBytes(typ string, body ...[]byte) []byte {
+	content := bytes.Join(body, nil)
+	box := make([]byte, 8, 8+len(content))
+	binary.BigEndian.PutUint32(box, uint32(8+len(content)))
+	copy(box[4:], typ)
+	return append(box, content...)
+}
+
+// mp4File returns an M4A file with a single sound track
+func mp4File(timescale uint32, duration uint32, sampleRate uint16, channels uint16) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], timescale)
+	binary.BigEndian.PutUint32(mvhd[16:20], duration)
+
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint32(mdhd[12:16], uint32(sampleRate))
+
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], "soun")
+
+	stsd := make([]byte, 8+36)
+	binary.BigEndian.PutUint32(stsd[4:8], 1)
+	entry := stsd[8:]
+	binary.BigEndian.PutUint32(entry[0:4], 36)
+	copy(entry[4:8], "mp4a")
+	binary.BigEndian.PutUint16(entry[24:26], channels)
+	binary.BigEndian.PutUint32(entry[32:36], uint32(sampleRate)<<16)
+
+	return bytes.Join([][]byte{
+		boxBytes("ftyp", []byte("M4A \x00\x00\x00\x00")),
+		boxBytes("moov",
+			boxBytes("mvhd", mvhd),
+			boxBytes("trak",
+				boxBytes("mdia",
+					boxBytes("mdhd", mdhd),
+					boxBytes("hdlr", hdlr),
+					boxBytes("minf", boxBytes("stbl", boxBytes("stsd", stsd)))))),
+	}, nil)
+}
+
+func probeBytes(data []byte, contentType string) (*Info, error) {
+	return Probe(bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		want        Info
+	}{
+		{
+			name:        "wav",
+			contentType: "audio/wav",
+			data:        wavFile(t, 16000, 2, 32000),
+			want:        Info{Duration: 2 * time.Second, SampleRate: 16000, Channels: 2, Bitrate: 512000},
+		},
+		{
+			name:        "mp3 with Xing header",
+			contentType: "audio/mpeg",
+			data:        mp3File(1225, 4000),
+			want:        Info{Duration: 32 * time.Second, SampleRate: 44100, Channels: 2, Bitrate: 1000},
+		},
+		{
+			name:        "mp3 at constant bitrate",
+			contentType: "audio/mpeg",
+			data:        mp3File(0, 16000),
+			want:        Info{Duration: time.Second, SampleRate: 44100, Channels: 2, Bitrate: 128000},
+		},
+		{
+			name:        "flac",
+			contentType: "audio/flac",
+			data:        flacFile(48000, 1, 144000),
+			want:        Info{Duration: 3 * time.Second, SampleRate: 48000, Channels: 1, Bitrate: 112},
+		},
+		{
+			name:        "opus",
+			contentType: "audio/ogg",
+			data:        append(oggPage(0, opusHead(1, 312, 16000)), oggPage(48312, []byte{0})...),
+			want:        Info{Duration: time.Second, SampleRate: 16000, Channels: 1, Bitrate: 608},
+		},
+		{
+			name:        "vorbis",
+			contentType: "audio/ogg",
+			data:        append(oggPage(0, vorbisHead(2, 44100, 96000)), oggPage(88200, []byte{0})...),
+			want:        Info{Duration: 2 * time.Second, SampleRate: 44100, Channels: 2, Bitrate: 96000},
+		},
+		{
+			name:        "m4a",
+			contentType: "audio/mp4",
+			data:        mp4File(1000, 1500, 22050, 1),
+			want:        Info{Duration: 1500 * time.Millisecond, SampleRate: 22050, Channels: 1, Bitrate: 1493},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := probeBytes(tt.data, tt.contentType)
+			if err != nil {
+				t.Fatalf("Probe() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Probe() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProbeLargeCounts checks that counts whose duration overflows nanoseconds still
+// give the stated duration, so that it is rejected by the upload limit
+func TestProbeLargeCounts(t *testing.T) {
+	info, err := probeBytes(mp3File(math.MaxUint32, 1024), "audio/mpeg")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if want := 112195000 * time.Second; info.Duration < want {
+		t.Errorf("Probe() duration = %v, want at least %v", info.Duration, want)
+	}
+}
+
+func TestProbeRejectsMalformedHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+	}{
+		{"unsupported format", "audio/aac", []byte{0xFF, 0xF1, 0x50, 0x80}},
+		{"empty wav", "audio/wav", wavFile(t, 16000, 1, 0)},
+		{"mp3 without frame", "audio/mpeg", []byte("ID3\x03\x00\x00\x00\x00\x00\x00junk")},
+		{"mp3 free format", "audio/mpeg", func() []byte {
+			data := mp3File(0, 64)
+			data[2] &= 0x0F
+			return data
+		}()},
+		{"flac without STREAMINFO", "audio/flac", append([]byte("fLaC\x01"), make([]byte, 40)...)},
+		{"flac zero sample rate", "audio/flac", flacFile(0, 1, 1000)},
+		{"flac duration overflow", "audio/flac", flacFile(1, 1, 1<<36-1)},
+		{"ogg short page", "audio/ogg", append([]byte("OggS"), make([]byte, 36)...)},
+		{"ogg segment table past end", "audio/ogg", func() []byte {
+			data := append([]byte("OggS"), make([]byte, 60)...)
+			data[26] = 255
+			return data
+		}()},
+		{"ogg unknown codec", "audio/ogg", oggPage(0, []byte("\x80theora-----------------------"))},
+		{"ogg zero rate", "audio/ogg", oggPage(0, vorbisHead(2, 0, 0))},
+		{"ogg without granule", "audio/ogg", oggPage(-1, opusHead(1, 0, 48000))},
+		{"ogg granule overflow", "audio/ogg", oggPage(math.MaxInt64, opusHead(1, 0, 48000))},
+		{"ogg granule before pre-skip", "audio/ogg", oggPage(100, opusHead(1, 312, 48000))},
+		{"mp4 without moov", "audio/mp4", boxBytes("ftyp", []byte("M4A \x00\x00\x00\x00"))},
+		{"mp4 box past end", "audio/mp4", func() []byte {
+			data := mp4File(1000, 1500, 22050, 1)
+			binary.BigEndian.PutUint32(data[0:4], math.MaxUint32)
+			return data
+		}()},
+		{"mp4 huge 64-bit box", "audio/mp4", []byte("\x00\x00\x00\x01moov\x7f\xff\xff\xff\xff\xff\xff\xff")},
+		{"mp4 zero timescale", "audio/mp4", mp4File(0, 1500, 22050, 1)},
+		{"mp4 zero duration", "audio/mp4", mp4File(1000, 0, 22050, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := probeBytes(tt.data, tt.contentType)
+			if err == nil {
+				t.Fatalf("Probe() = %+v, want error", *info)
+			}
+		})
+	}
+}
+
+func TestProbeUnsupportedErrors(t *testing.T) {
+	data := append([]byte("OggS"), make([]byte, 36)...)
+	if _, err := probeBytes(data, "audio/ogg"); !stderrors.Is(err, ErrProbeUnsupported) {
+		t.Errorf("Probe() error = %v, want ErrProbeUnsupported", err)
+	}
+}
+
+// TestProbeTruncated probes every prefix of valid files, as uploads cut short would be
+func TestProbeTruncated(t *testing.T) {
+	files := map[string][]byte{
+		"audio/wav":  wavFile(t, 8000, 1, 100),
+		"audio/mpeg": mp3File(100, 200),
+		"audio/flac": flacFile(44100, 2, 44100),
+		"audio/ogg":  append(oggPage(0, opusHead(2, 0, 48000)), oggPage(48000, []byte{0})...),
+		"audio/mp4":  mp4File(1000, 1000, 44100, 2),
+	}
+
+	for contentType, data := range files {
+		for n := range len(data) {
+			info, err := probeBytes(data[:n], contentType)
+			if err == nil && info.Duration <= 0 {
+				t.Errorf("Probe(%s, %d bytes) = %+v, want a positive duration", contentType, n, *info)
+			}
+		}
+	}
+}
+
+func FuzzProbe(f *testing.F) {
+	f.Add(mp3File(100, 200), "audio/mpeg")
+	f.Add(flacFile(44100, 2, 44100), "audio/flac")
+	f.Add(append(oggPage(0, opusHead(2, 0, 48000)), oggPage(48000, []byte{0})...), "audio/ogg")
+	f.Add(mp4File(1000, 1000, 44100, 2), "audio/mp4")
+
+	f.Fuzz(func(t *testing.T, data []byte, contentType string) {
+		info, err := probeBytes(data, contentType)
+		if err == nil && info.Duration <= 0 {
+			t.Errorf("Probe() = %+v, want a positive duration", *info)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+// Package media identifies audio and video containers from their content
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Number of bytes inspected after any ID3 tag
+const sniffLen = 64
+
+// Content type families. Types in the same family share a container, so a file of one of
+// them may be labeled with any other.
+var families = map[string]string{
+	"audio/wav":        "wav",
+	"audio/wave":       "wav",
+	"audio/x-wav":      "wav",
+	"audio/vnd.wave":   "wav",
+	"audio/mpeg":       "mpeg",
+	"audio/mp3":        "mpeg",
+	"audio/mpeg3":      "mpeg",
+	"audio/x-mpeg-3":   "mpeg",
+	"audio/aac":        "aac",
+	"audio/aacp":       "aac",
+	"audio/x-aac":      "aac",
+	"audio/flac":       "flac",
+	"audio/x-flac":     "flac",
+	"audio/ogg":        "ogg",
+	"audio/opus":       "ogg",
+	"application/ogg":  "ogg",
+	"audio/webm":       "matroska",
+	"video/webm":       "matroska",
+	"audio/x-matroska": "matroska",
+	"video/x-matroska": "matroska",
+	"audio/mp4":        "mp4",
+	"audio/m4a":        "mp4",
+	"audio/x-m4a":      "mp4",
+	"video/mp4":        "mp4",
+	"video/x-m4v":      "mp4",
+	"video/quicktime":  "mp4",
+	"audio/x-ms-wma":   "asf",
+	"video/x-ms-wmv":   "asf",
+	"video/x-ms-asf":   "asf",
+	"video/x-msvideo":  "avi",
+	"video/avi":        "avi",
+	"video/x-flv":      "flv",
+	"audio/amr":        "amr",
+	"audio/amr-wb":     "amr",
+	"video/mpeg":       "mpeg-ps",
+}
+
+var asfHeaderGUID = []byte{0x30, 0x26, 0xB2, 0x75, 0x8E, 0x66, 0xCF, 0x11, 0xA6, 0xD9, 0x00, 0xAA, 0x00, 0x62, 0xCE, 0x6C}
+
+// Sniff returns the content type of an audio or video file from its first bytes, or an
+// empty string when the container is not recognized. An ID3 tag at the start of the file
+// is skipped, since it may precede MP3, AAC and FLAC streams.
+func Sniff(r io.Reader) (string, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(10)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	tagged := false
+	if len(head) == 10 && string(head[0:3]) == "ID3" {
+		tagged = true
+		size := int64(head[6]&0x7F)<<21 | int64(head[7]&0x7F)<<14 | int64(head[8]&0x7F)<<7 | int64(head[9]&0x7F)
+		if head[5]&0x10 != 0 {
+			// Footer present
+			size += 10
+		}
+		if _, err := io.CopyN(io.Discard, br, 10+size); err != nil {
+			if err == io.EOF {
+				return "audio/mpeg", nil
+			}
+			return "", err
+		}
+	}
+
+	head, err = br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	contentType := sniffContainer(head)
+	if contentType == "" && tagged {
+		// The tag may be followed by padding or junk before the first frame
+		return "audio/mpeg", nil
+	}
+	return contentType, nil
+}
+
+func sniffContainer(b []byte) string {
+	switch {
+	case len(b) >= 12 && string(b[0:4]) == "RIFF" && string(b[8:12]) == "WAVE":
+		return "audio/wav"
+	case len(b) >= 12 && string(b[0:4]) == "RIFF" && string(b[8:12]) == "AVI ":
+		return "video/x-msvideo"
+	case bytes.HasPrefix(b, []byte("fLaC")):
+		return "audio/flac"
+	case bytes.HasPrefix(b, []byte("OggS")):
+		if len(b) >= 36 && string(b[28:36]) == "OpusHead" {
+			return "audio/opus"
+		}
+		return "audio/ogg"
+	case bytes.HasPrefix(b, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		if bytes.Contains(b, []byte("webm")) {
+			return "video/webm"
+		}
+		return "video/x-matroska"
+	case len(b) >= 12 && string(b[4:8]) == "ftyp":
+		return mp4Type(string(b[8:12]))
+	case bytes.HasPrefix(b, asfHeaderGUID):
+		return "audio/x-ms-wma"
+	case bytes.HasPrefix(b, []byte("FLV\x01")):
+		return "video/x-flv"
+	case bytes.HasPrefix(b, []byte("#!AMR-WB\n")):
+		return "audio/amr-wb"
+	case bytes.HasPrefix(b, []byte("#!AMR\n")):
+		return "audio/amr"
+	case bytes.HasPrefix(b, []byte{0x00, 0x00, 0x01, 0xBA}):
+		return "video/mpeg"
+	case len(b) >= 2 && b[0] == 0xFF && b[1]&0xF6 == 0xF0:
+		// ADTS frame header: sync word with layer 0
+		return "audio/aac"
+	case len(b) >= 4 && isMPEGAudioFrame(binary.BigEndian.Uint32(b)):
+		return "audio/mpeg"
+	}
+	return ""
+}
+
+// mp4Type returns the content type of an ISO base media file from its major brand
+func mp4Type(brand string) string {
+	switch brand {
+	case "M4A ", "M4B ", "M4P ":
+		return "audio/mp4"
+	case "qt  ":
+		return "video/quicktime"
+	}
+	return "video/mp4"
+}
+
+// isMPEGAudioFrame reports whether header is a plausible MPEG audio frame header
+func isMPEGAudioFrame(header uint32) bool {
+	sync := header >> 21
+	version := (header >> 19) & 0x3
+	layer := (header >> 17) & 0x3
+	bitrate := (header >> 12) & 0xF
+	sampleRate := (header >> 10) & 0x3
+	return sync == 0x7FF && version != 1 && layer != 0 && bitrate != 0xF && sampleRate != 3
+}
+
+// IsRaw reports whether contentType is headerless audio, which has no signature to sniff
+func IsRaw(contentType string) bool {
+	return contentType == "audio/pcm"
+}
+
+// IsGeneric reports whether contentType says nothing about the format of a file, as sent
+// by clients that do not know it
+func IsGeneric(contentType string) bool {
+	return contentType == "" || contentType == "application/octet-stream" || contentType == "binary/octet-stream"
+}
+
+// SameFamily reports whether two content types share a container
+func SameFamily(a, b string) bool {
+	family, ok := families[a]
+	return ok && family == families[b]
+}
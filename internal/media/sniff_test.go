@@ -0,0 +1,80 @@
+package media
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	id3 := []byte("ID3\x04\x00\x00\x00\x00\x00\x04tag.")
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, ""},
+		{"text", []byte("hello, world"), ""},
+		{"wav", []byte("RIFF\x24\x00\x00\x00WAVEfmt "), "audio/wav"},
+		{"avi", []byte("RIFF\x24\x00\x00\x00AVI LIST"), "video/x-msvideo"},
+		{"truncated riff", []byte("RIFF\x24\x00"), ""},
+		{"flac", []byte("fLaC\x00\x00\x00\x22"), "audio/flac"},
+		{"opus", oggPage(0, opusHead(1, 0, 48000)), "audio/opus"},
+		{"vorbis", oggPage(0, vorbisHead(2, 44100, 0)), "audio/ogg"},
+		{"truncated ogg", []byte("OggS"), "audio/ogg"},
+		{"webm", []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x84webm"), "video/webm"},
+		{"matroska", []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x88matroska"), "video/x-matroska"},
+		{"m4a", []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00"), "audio/mp4"},
+		{"quicktime", []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00"), "video/quicktime"},
+		{"mp4", []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"), "video/mp4"},
+		{"truncated ftyp", []byte("\x00\x00\x00\x20ftyp"), ""},
+		{"wma", append(bytes.Clone(asfHeaderGUID), 0, 0), "audio/x-ms-wma"},
+		{"flv", []byte("FLV\x01\x05"), "video/x-flv"},
+		{"amr", []byte("#!AMR\n\x3c"), "audio/amr"},
+		{"amr-wb", []byte("#!AMR-WB\n\x04"), "audio/amr-wb"},
+		{"mpeg program stream", []byte("\x00\x00\x01\xba\x44"), "video/mpeg"},
+		{"adts", []byte("\xff\xf1\x50\x80"), "audio/aac"},
+		{"mp3", mp3File(0, 8), "audio/mpeg"},
+		{"mp3 reserved version", []byte("\xff\xeb\x90\x64"), ""},
+		{"mp3 bad bitrate", []byte("\xff\xfb\xf0\x64"), ""},
+		{"mp3 bad sample rate", []byte("\xff\xfb\x9c\x64"), ""},
+		{"mp3 after id3", append(bytes.Clone(id3), mp3File(0, 8)...), "audio/mpeg"},
+		{"flac after id3", append(bytes.Clone(id3), "fLaC"...), "audio/flac"},
+		{"junk after id3", append(bytes.Clone(id3), "junk"...), "audio/mpeg"},
+		{"id3 past end", []byte("ID3\x04\x00\x00\x00\x00\x7f\x7f"), "audio/mpeg"},
+		{"truncated id3", []byte("ID3\x04"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sniff(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Sniff() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Sniff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameFamily(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"audio/wav", "audio/x-wav", true},
+		{"audio/opus", "audio/ogg", true},
+		{"audio/webm", "video/webm", true},
+		{"audio/m4a", "video/quicktime", true},
+		{"audio/mpeg", "audio/aac", false},
+		{"audio/ogg", "audio/webm", false},
+		{"text/plain", "text/plain", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameFamily(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameFamily(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
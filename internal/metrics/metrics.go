@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"qwen3-compatibility/internal/errors"
+)
+
+const namespace = "qwen3_compat"
+
+// Upstream call phases
+const (
+	PhasePolicy    = "policy"
+	PhaseOSSUpload = "oss_upload"
+	PhaseASR       = "asr"
+	PhaseTask      = "task"
+)
+
+// OtherModel is the model label of models that were not registered with SetModels
+const OtherModel = "other"
+
+var registry = prometheus.NewRegistry()
+
+// knownModels are the models recorded by name. It is set before the servers start.
+var knownModels = map[string]bool{}
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by route, method, status and model.",
+	}, []string{"route", "method", "status", "model"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method, status and model.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"route", "method", "status", "model"})
+
+	httpRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Number of HTTP requests currently being served by route.",
+	}, []string{"route"})
+
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Upstream call latency by phase (policy, oss_upload, asr, task) and outcome.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"phase", "outcome"})
+
+	upstreamInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_in_flight",
+		Help:      "Number of upstream calls currently in progress by phase.",
+	}, []string{"phase"})
+
+	upstreamErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_errors_total",
+		Help:      "Upstream errors by phase and upstream error code.",
+	}, []string{"phase", "code"})
+
+	uploadBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes_total",
+		Help:      "Total bytes uploaded to OSS by model.",
+	}, []string{"model"})
+
+	audioSecondsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audio_seconds_transcribed_total",
+		Help:      "Total seconds of audio transcribed by model.",
+	}, []string{"model"})
+
+	chunksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transcription_chunks_total",
+		Help:      "Total chunks and segments recordings were split into, by model.",
+	}, []string{"model"})
+
+	transcodesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transcodes_total",
+		Help:      "Total ffmpeg transcodes before upload, by result.",
+	}, []string{"result"})
+
+	fallbacksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "model_fallbacks_total",
+		Help:      "Total transcriptions retried with a fallback model, by failed model, fallback model and error class.",
+	}, []string{"model", "fallback", "class"})
+
+	silenceTrimmedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "silence_seconds_trimmed_total",
+		Help:      "Total seconds of silence removed from audio before upload, by model.",
+	}, []string{"model"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		upstreamDuration,
+		upstreamInFlight,
+		upstreamErrorsTotal,
+		uploadBytesTotal,
+		audioSecondsTotal,
+		chunksTotal,
+		silenceTrimmedTotal,
+		transcodesTotal,
+		fallbacksTotal,
+	)
+}
+
+// SetModels sets the models recorded by name in the model label, so that the names sent
+// by callers cannot add label values without bound. It must be called before any
+// metrics are recorded.
+func SetModels(models []string) {
+	knownModels = make(map[string]bool, len(models))
+	for _, model := range models {
+		knownModels[strings.ToLower(model)] = true
+	}
+}
+
+// modelLabel returns the label value of a model: its lowercased name if it is known,
+// otherwise OtherModel. Requests without a model keep an empty label.
+func modelLabel(model string) string {
+	if model == "" {
+		return ""
+	}
+	model = strings.ToLower(model)
+	if knownModels[model] {
+		return model
+	}
+	return OtherModel
+}
+
+// Handler returns the HTTP handler serving the metrics registry
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RequestStarted marks an HTTP request as in flight and returns a function that records its completion
+func RequestStarted(route, method string) func(status, model string) {
+	start := time.Now()
+	inFlight := httpRequestsInFlight.WithLabelValues(route)
+	inFlight.Inc()
+
+	return func(status, model string) {
+		inFlight.Dec()
+		model = modelLabel(model)
+		httpRequestsTotal.WithLabelValues(route, method, status, model).Inc()
+		httpRequestDuration.WithLabelValues(route, method, status, model).Observe(time.Since(start).Seconds())
+	}
+}
+
+// UpstreamStarted marks an upstream call as in flight and returns a function that records its result
+func UpstreamStarted(phase string) func(err error) {
+	start := time.Now()
+	inFlight := upstreamInFlight.WithLabelValues(phase)
+	inFlight.Inc()
+
+	return func(err error) {
+		inFlight.Dec()
+
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+			upstreamErrorsTotal.WithLabelValues(phase, ErrorCode(err)).Inc()
+		}
+		upstreamDuration.WithLabelValues(phase, outcome).Observe(time.Since(start).Seconds())
+	}
+}
+
+// AddUploadBytes records bytes uploaded to OSS
+func AddUploadBytes(model string, n int64) {
+	if n > 0 {
+		uploadBytesTotal.WithLabelValues(modelLabel(model)).Add(float64(n))
+	}
+}
+
+// AddAudioSeconds records seconds of audio transcribed
+func AddAudioSeconds(model string, seconds float64) {
+	if seconds > 0 {
+		audioSecondsTotal.WithLabelValues(modelLabel(model)).Add(seconds)
+	}
+}
+
+// AddChunks records the number of chunks or segments a recording was split into
+func AddChunks(model string, n int) {
+	chunksTotal.WithLabelValues(modelLabel(model)).Add(float64(n))
+}
+
+// AddSilenceTrimmed records the seconds of silence removed from audio before upload
+func AddSilenceTrimmed(model string, seconds float64) {
+	silenceTrimmedTotal.WithLabelValues(modelLabel(model)).Add(seconds)
+}
+
+// AddTranscode records an ffmpeg run before upload; result is ok, failed or timeout
+func AddTranscode(result string) {
+	transcodesTotal.WithLabelValues(result).Inc()
+}
+
+// AddFallback records a transcription retried with a fallback model after model failed
+// with an error of class
+func AddFallback(model, fallback, class string) {
+	fallbacksTotal.WithLabelValues(modelLabel(model), modelLabel(fallback), class).Inc()
+}
+
+// ErrorCode classifies an upstream error, preferring the error code reported by the upstream service
+func ErrorCode(err error) string {
+	if apiErr, ok := errors.IsAPIError(err); ok && apiErr.UpstreamCode != "" {
+		return apiErr.UpstreamCode
+	}
+
+	if stderrors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+
+	return "unknown"
+}
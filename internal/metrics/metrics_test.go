@@ -0,0 +1,26 @@
+package metrics
+
+import "testing"
+
+func TestModelLabel(t *testing.T) {
+	SetModels([]string{"qwen3-asr-flash", "Whisper-1"})
+	defer SetModels(nil)
+
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"", ""},
+		{"qwen3-asr-flash", "qwen3-asr-flash"},
+		{"QWEN3-ASR-FLASH", "qwen3-asr-flash"},
+		{"whisper-1", "whisper-1"},
+		{"qwen3-asr-flash-x", OtherModel},
+		{"anything a caller sends", OtherModel},
+	}
+
+	for _, tt := range tests {
+		if got := modelLabel(tt.model); got != tt.want {
+			t.Errorf("modelLabel(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+	"qwen3-compatibility/internal/store"
+)
+
+// InternalAuth authenticates requests dispatched in-process using the caller stored in the
+// request context with requestid.WithCaller. Virtual keys are looked up again so that disabled keys stop working.
+// It must only be used on routers that are not exposed over the network.
+func InternalAuth(keyStore *store.KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := requestid.CallerFromContext(c.Request.Context())
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error: "Missing caller",
+			})
+			c.Abort()
+			return
+		}
+
+		if caller.VirtualKeyID != "" {
+			var virtualKey *models.VirtualKey
+			if keyStore != nil {
+				virtualKey, _ = keyStore.Get(caller.VirtualKeyID)
+			}
+			if virtualKey == nil || virtualKey.Disabled {
+				c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+					Error: "API key is disabled",
+				})
+				c.Abort()
+				return
+			}
+
+			c.Set(VirtualKeyContextKey, virtualKey)
+			c.Set(APIKeyContextKey, virtualKey.UpstreamKey)
+			WithLogAttrs(c, "virtual_key_id", virtualKey.ID)
+			c.Next()
+			return
+		}
+
+		c.Set(APIKeyContextKey, caller.APIKey)
+		WithLogAttrs(c, "api_key_fp", logging.Fingerprint(caller.APIKey))
+		c.Next()
+	}
+}
@@ -1,56 +1,164 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/metrics"
 	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+	"qwen3-compatibility/internal/store"
 )
 
-const APIKeyContextKey = "api_key"
+const (
+	APIKeyContextKey     = "api_key"
+	VirtualKeyContextKey = "virtual_key"
+	ModelContextKey      = "model"
+	RequestIDContextKey  = "request_id"
+)
+
+// AuthMiddleware extracts and validates the API key from the Authorization header.
+// Registered virtual keys are resolved to their upstream DashScope key; other keys
+// are forwarded as-is unless requireVirtualKey is set.
+func AuthMiddleware(keyStore *store.KeyStore, requireVirtualKey bool) gin.HandlerFunc {
+	limiter := newRateLimiter()
 
-// AuthMiddleware extracts and validates the API key from the Authorization header
-func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Error: "Missing Authorization header",
-			})
-			c.Abort()
+		apiKey, ok := bearerToken(c)
+		if !ok {
 			return
 		}
 
-		// Expected format: "Bearer <api_key>"
-		const bearerPrefix = "Bearer "
-		if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
+		if keyStore != nil {
+			if virtualKey, found := keyStore.Authenticate(apiKey); found {
+				if virtualKey.Disabled {
+					c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+						Error: "API key is disabled",
+					})
+					c.Abort()
+					return
+				}
+
+				if !limiter.Allow(virtualKey.ID, virtualKey.Limits.RequestsPerMinute) {
+					c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+						Error: "Rate limit exceeded for API key",
+					})
+					c.Abort()
+					return
+				}
+
+				c.Set(VirtualKeyContextKey, virtualKey)
+				c.Set(APIKeyContextKey, virtualKey.UpstreamKey)
+				WithLogAttrs(c, "api_key_fp", logging.Fingerprint(apiKey), "virtual_key_id", virtualKey.ID)
+				c.Next()
+				return
+			}
+		}
+
+		if requireVirtualKey {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Error: "Invalid Authorization header format. Expected: Bearer <api_key>",
+				Error: "Invalid API key",
 			})
 			c.Abort()
 			return
 		}
 
-		apiKey := authHeader[len(bearerPrefix):]
-		if apiKey == "" {
+		// Store API key in context for downstream handlers
+		c.Set(APIKeyContextKey, apiKey)
+		WithLogAttrs(c, "api_key_fp", logging.Fingerprint(apiKey))
+		c.Next()
+	}
+}
+
+// AdminAuth protects admin routes with a dedicated admin token
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminToken, ok := bearerToken(c)
+		if !ok {
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(adminToken), []byte(token)) != 1 {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Error: "API key is empty",
+				Error: "Invalid admin token",
 			})
 			c.Abort()
 			return
 		}
 
-		// Store API key in context for downstream handlers
-		c.Set(APIKeyContextKey, apiKey)
 		c.Next()
 	}
 }
 
+// GetVirtualKey returns the virtual key resolved by AuthMiddleware, if any
+func GetVirtualKey(c *gin.Context) *models.VirtualKey {
+	value, exists := c.Get(VirtualKeyContextKey)
+	if !exists {
+		return nil
+	}
+	virtualKey, _ := value.(*models.VirtualKey)
+	return virtualKey
+}
+
+// OwnerID identifies the caller for resources such as jobs: the virtual key ID when a virtual
+// key was used, otherwise a SHA-256 hash of the API key
+func OwnerID(c *gin.Context) string {
+	if virtualKey := GetVirtualKey(c); virtualKey != nil {
+		return virtualKey.ID
+	}
+	sum := sha256.Sum256([]byte(c.GetString(APIKeyContextKey)))
+	return "key_" + hex.EncodeToString(sum[:])
+}
+
+// bearerToken extracts the bearer token from the Authorization header.
+// It writes an error response and aborts the request when the header is invalid.
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Missing Authorization header",
+		})
+		c.Abort()
+		return "", false
+	}
+
+	// Expected format: "Bearer <api_key>"
+	const bearerPrefix = "Bearer "
+	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "Invalid Authorization header format. Expected: Bearer <api_key>",
+		})
+		c.Abort()
+		return "", false
+	}
+
+	token := authHeader[len(bearerPrefix):]
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: "API key is empty",
+		})
+		c.Abort()
+		return "", false
+	}
+
+	return token, true
+}
+
 // ErrorHandler provides centralized error handling
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -64,29 +172,133 @@ func ErrorHandler() gin.HandlerFunc {
 	}
 }
 
+// RequestID accepts the caller's X-Request-ID or generates one, and makes it available to
+// handlers, logs and upstream calls. Every response carries X-Request-ID and, once DashScope
+// has been called, X-DashScope-Request-ID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		ctx := requestid.WithID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(RequestIDContextKey, id)
+
+		c.Header(requestid.Header, id)
+		c.Writer = &requestIDWriter{
+			ResponseWriter: c.Writer,
+			upstream:       requestid.UpstreamFromContext(ctx),
+		}
+
+		c.Next()
+	}
+}
+
+// RequestLogger attaches a per-request logger carrying the request ID to the request context.
+// AuthMiddleware adds the API key fingerprint and handlers add the model via WithLogAttrs.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetString(RequestIDContextKey)
+		if requestID == "" {
+			requestID = requestid.New()
+			c.Set(RequestIDContextKey, requestID)
+		}
+
+		logger := slog.Default().With("request_id", requestID)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+	}
+}
+
+// WithLogAttrs adds attributes to the request logger for the rest of the request
+func WithLogAttrs(c *gin.Context, args ...any) {
+	logger := logging.FromContext(c.Request.Context()).With(args...)
+	c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+}
+
 // Logger provides request logging
 func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		attrs := []any{
+			"client_ip", c.ClientIP(),
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"proto", c.Request.Proto,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", c.Errors.String())
+		}
+
+		logging.FromContext(c.Request.Context()).Info("Request completed", attrs...)
+	}
+}
+
+// Metrics records Prometheus request metrics. Handlers report the model via ModelContextKey.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		done := metrics.RequestStarted(route, c.Request.Method)
+		c.Next()
+
+		done(strconv.Itoa(c.Writer.Status()), c.GetString(ModelContextKey))
+	}
+}
+
+// Tracing starts a server span for each request, continuing any W3C trace context sent by the caller
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := otel.Tracer("qwen3-compatibility").Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("request.id", c.GetString(RequestIDContextKey)),
+			),
 		)
-	})
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if model := c.GetString(ModelContextKey); model != "" {
+			span.SetAttributes(attribute.String("asr.model", model))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
 }
 
 // CORS provides Cross-Origin Resource Sharing middleware
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID, traceparent, tracestate")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, X-DashScope-Request-ID, X-ASR-Model, X-ASR-Provider")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -100,14 +312,15 @@ func CORS() gin.HandlerFunc {
 // Recovery handles panics and returns a 500 error
 func Recovery() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		logger := logging.FromContext(c.Request.Context())
 		if err, ok := recovered.(string); ok {
-			log.Printf("Panic recovered: %s", err)
+			logger.Error("Panic recovered", "panic", err)
 			handleError(c, fmt.Errorf("internal server error: %s", err))
 		} else if err, ok := recovered.(error); ok {
-			log.Printf("Panic recovered: %v", err)
+			logger.Error("Panic recovered", "error", err)
 			handleError(c, fmt.Errorf("internal server error: %v", err))
 		} else {
-			log.Printf("Panic recovered with unknown type: %v", recovered)
+			logger.Error("Panic recovered with unknown type", "panic", fmt.Sprintf("%v", recovered))
 			handleError(c, fmt.Errorf("internal server error"))
 		}
 	})
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter enforces per-key request limits using one-minute fixed windows
+type rateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Allow records a request for key and reports whether it is within limit.
+// A limit of zero or less disables rate limiting.
+func (l *rateLimiter) Allow(key string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, ok := l.windows[key]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		l.windows[key] = &rateWindow{start: now, count: 1}
+		return true
+	}
+
+	if window.count >= limit {
+		return false
+	}
+
+	window.count++
+	return true
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"qwen3-compatibility/internal/requestid"
+)
+
+// requestIDWriter sets the X-DashScope-Request-ID header from the IDs recorded by upstream
+// calls right before the response headers are written
+type requestIDWriter struct {
+	gin.ResponseWriter
+	upstream *requestid.Upstream
+}
+
+func (w *requestIDWriter) WriteHeaderNow() {
+	w.setUpstreamHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *requestIDWriter) Write(data []byte) (int, error) {
+	w.setUpstreamHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *requestIDWriter) WriteString(s string) (int, error) {
+	w.setUpstreamHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *requestIDWriter) setUpstreamHeader() {
+	if w.Written() || w.upstream == nil {
+		return
+	}
+	if ids := w.upstream.String(); ids != "" {
+		w.Header().Set(requestid.UpstreamHeader, ids)
+	}
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Batch statuses
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// IsTerminal reports whether a batch in this status will not change anymore
+func (s BatchStatus) IsTerminal() bool {
+	switch s {
+	case BatchStatusFailed, BatchStatusCompleted, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Batch as persisted in the batch store
+type Batch struct {
+	ID               string             `json:"id"`
+	Endpoint         string             `json:"endpoint"`
+	InputFileID      string             `json:"input_file_id"`
+	CompletionWindow string             `json:"completion_window"`
+	Status           BatchStatus        `json:"status"`
+	OutputFileID     string             `json:"output_file_id,omitempty"`
+	ErrorFileID      string             `json:"error_file_id,omitempty"`
+	Errors           []BatchError       `json:"errors,omitempty"`
+	RequestCounts    BatchRequestCounts `json:"request_counts"`
+	Metadata         map[string]string  `json:"metadata,omitempty"`
+	Owner            string             `json:"owner"`
+	APIKey           string             `json:"api_key,omitempty"`
+	VirtualKeyID     string             `json:"virtual_key_id,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	InProgressAt     time.Time          `json:"in_progress_at,omitzero"`
+	ExpiresAt        time.Time          `json:"expires_at"`
+	FinalizingAt     time.Time          `json:"finalizing_at,omitzero"`
+	CompletedAt      time.Time          `json:"completed_at,omitzero"`
+	FailedAt         time.Time          `json:"failed_at,omitzero"`
+	ExpiredAt        time.Time          `json:"expired_at,omitzero"`
+	CancellingAt     time.Time          `json:"cancelling_at,omitzero"`
+	CancelledAt      time.Time          `json:"cancelled_at,omitzero"`
+}
+
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// Validation error of a batch input file
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Param   string `json:"param,omitempty"`
+}
+
+// Request to create a batch
+type CreateBatchRequest struct {
+	InputFileID      string            `json:"input_file_id" binding:"required"`
+	Endpoint         string            `json:"endpoint" binding:"required"`
+	CompletionWindow string            `json:"completion_window" binding:"required"`
+	Metadata         map[string]string `json:"metadata"`
+}
+
+// OpenAI-compatible batch object
+type BatchResponse struct {
+	ID               string             `json:"id"`
+	Object           string             `json:"object"`
+	Endpoint         string             `json:"endpoint"`
+	Errors           *BatchErrorList    `json:"errors"`
+	InputFileID      string             `json:"input_file_id"`
+	CompletionWindow string             `json:"completion_window"`
+	Status           BatchStatus        `json:"status"`
+	OutputFileID     *string            `json:"output_file_id"`
+	ErrorFileID      *string            `json:"error_file_id"`
+	CreatedAt        int64              `json:"created_at"`
+	InProgressAt     *int64             `json:"in_progress_at"`
+	ExpiresAt        *int64             `json:"expires_at"`
+	FinalizingAt     *int64             `json:"finalizing_at"`
+	CompletedAt      *int64             `json:"completed_at"`
+	FailedAt         *int64             `json:"failed_at"`
+	ExpiredAt        *int64             `json:"expired_at"`
+	CancellingAt     *int64             `json:"cancelling_at"`
+	CancelledAt      *int64             `json:"cancelled_at"`
+	RequestCounts    BatchRequestCounts `json:"request_counts"`
+	Metadata         map[string]string  `json:"metadata"`
+}
+
+type BatchErrorList struct {
+	Object string       `json:"object"`
+	Data   []BatchError `json:"data"`
+}
+
+type BatchListResponse struct {
+	Object  string          `json:"object"`
+	Data    []BatchResponse `json:"data"`
+	FirstID *string         `json:"first_id"`
+	LastID  *string         `json:"last_id"`
+	HasMore bool            `json:"has_more"`
+}
+
+// One line of a batch input file
+type BatchInputLine struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// One line of a batch output or error file
+type BatchOutputLine struct {
+	ID       string               `json:"id"`
+	CustomID string               `json:"custom_id"`
+	Response *BatchOutputResponse `json:"response"`
+	Error    *BatchOutputError    `json:"error"`
+}
+
+type BatchOutputResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type BatchOutputError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewBatchResponse converts a stored batch into its API representation
+func NewBatchResponse(batch *Batch) BatchResponse {
+	response := BatchResponse{
+		ID:               batch.ID,
+		Object:           "batch",
+		Endpoint:         batch.Endpoint,
+		InputFileID:      batch.InputFileID,
+		CompletionWindow: batch.CompletionWindow,
+		Status:           batch.Status,
+		CreatedAt:        batch.CreatedAt.Unix(),
+		InProgressAt:     unixOrNil(batch.InProgressAt),
+		ExpiresAt:        unixOrNil(batch.ExpiresAt),
+		FinalizingAt:     unixOrNil(batch.FinalizingAt),
+		CompletedAt:      unixOrNil(batch.CompletedAt),
+		FailedAt:         unixOrNil(batch.FailedAt),
+		ExpiredAt:        unixOrNil(batch.ExpiredAt),
+		CancellingAt:     unixOrNil(batch.CancellingAt),
+		CancelledAt:      unixOrNil(batch.CancelledAt),
+		RequestCounts:    batch.RequestCounts,
+		Metadata:         batch.Metadata,
+	}
+	if batch.OutputFileID != "" {
+		response.OutputFileID = &batch.OutputFileID
+	}
+	if batch.ErrorFileID != "" {
+		response.ErrorFileID = &batch.ErrorFileID
+	}
+	if len(batch.Errors) > 0 {
+		response.Errors = &BatchErrorList{Object: "list", Data: batch.Errors}
+	}
+	return response
+}
+
+func unixOrNil(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// File purposes
+const (
+	FilePurposeBatch       = "batch"
+	FilePurposeBatchOutput = "batch_output"
+	FilePurposeUserData    = "user_data"
+)
+
+// File stored through the Files API
+type File struct {
+	ID          string    `json:"id"`
+	Filename    string    `json:"filename"`
+	Purpose     string    `json:"purpose"`
+	Bytes       int64     `json:"bytes"`
+	ContentType string    `json:"content_type,omitempty"`
+	Owner       string    `json:"owner"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Uploads caches the OSS uploads of the file, keyed by FileUploadKey
+	Uploads map[string]FileUpload `json:"uploads,omitempty"`
+}
+
+// OSS upload of a stored file, reused by transcriptions that reference the file
+type FileUpload struct {
+	OSSURL         string    `json:"oss_url"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	SilenceTrimmed float64   `json:"silence_trimmed,omitempty"`
+}
+
+// FileUploadKey identifies an OSS upload. Upload policies are issued per model and per
+// upstream key, identified by its fingerprint, and uploads with silence kept are
+// separate from trimmed ones.
+func FileUploadKey(model, keyFingerprint string, keepSilence bool) string {
+	key := model + ":" + keyFingerprint
+	if keepSilence {
+		key += ":keep_silence"
+	}
+	return key
+}
+
+type FileListResponse struct {
+	Object  string         `json:"object"`
+	Data    []FileResponse `json:"data"`
+	FirstID *string        `json:"first_id"`
+	LastID  *string        `json:"last_id"`
+	HasMore bool           `json:"has_more"`
+}
+
+// OpenAI-compatible file object
+type FileResponse struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+}
+
+// NewFileResponse converts a stored file into its API representation
+func NewFileResponse(file *File) FileResponse {
+	return FileResponse{
+		ID:        file.ID,
+		Object:    "file",
+		Bytes:     file.Bytes,
+		CreatedAt: file.CreatedAt.Unix(),
+		Filename:  file.Filename,
+		Purpose:   file.Purpose,
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Transcription parameters shared by synchronous requests and jobs
+type TranscriptionParams struct {
+	Model          string             `json:"model"`
+	Language       *SupportedLanguage `json:"language,omitempty"`
+	Prompt         string             `json:"prompt,omitempty"`
+	ResponseFormat ResponseFormat     `json:"response_format,omitempty"`
+	// KeepSilence uploads the audio without trimming silence
+	KeepSilence bool `json:"keep_silence,omitempty"`
+	// EnableITN converts spoken numbers to written form; nil uses the server default
+	EnableITN   *bool       `json:"enable_itn,omitempty"`
+	ChannelMode ChannelMode `json:"channel_mode,omitempty"`
+	// Diarize labels the speakers, using the DashScope file transcription task API
+	Diarize      bool `json:"diarize,omitempty"`
+	SpeakerCount int  `json:"speaker_count,omitempty"`
+	// Dictionaries are the replacement dictionaries applied to the transcript, in order
+	Dictionaries []string `json:"dictionaries,omitempty"`
+	// OutputScript converts Chinese transcripts to Traditional or Simplified characters
+	OutputScript OutputScript `json:"output_script,omitempty"`
+	// Redact lists the kinds of text masked in the transcript
+	Redact []RedactionCategory `json:"redact,omitempty"`
+	// Include lists the optional response fields requested with include[]
+	Include []ResponseInclude `json:"include,omitempty"`
+	// AllowedModels lists the models allowed by the caller's virtual key, empty when every
+	// model is allowed. Fallback models outside the list are skipped. Jobs read it from the
+	// key when they run, so it is not persisted.
+	AllowedModels []string `json:"-"`
+}
+
+// AllowsModel reports whether the caller may be served by the given model
+func (p *TranscriptionParams) AllowsModel(model string) bool {
+	return len(p.AllowedModels) == 0 || slices.Contains(p.AllowedModels, model)
+}
+
+// Job statuses
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// IsTerminal reports whether a job in this status will not change anymore
+func (s JobStatus) IsTerminal() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCancelled
+}
+
+// Asynchronous transcription job as persisted in the job store
+type TranscriptionJob struct {
+	ID                 string                 `json:"id"`
+	Status             JobStatus              `json:"status"`
+	Params             TranscriptionParams    `json:"params"`
+	FileName           string                 `json:"file_name"`
+	FileSize           int64                  `json:"file_size"`
+	ContentType        string                 `json:"content_type"`
+	AudioPath          string                 `json:"audio_path"`
+	Owner              string                 `json:"owner"`
+	APIKey             string                 `json:"api_key,omitempty"`
+	VirtualKeyID       string                 `json:"virtual_key_id,omitempty"`
+	RequestID          string                 `json:"request_id"`
+	UpstreamRequestIDs []string               `json:"upstream_request_ids,omitempty"`
+	Result             *TranscriptionResponse `json:"result,omitempty"`
+	Error              *JobError              `json:"error,omitempty"`
+	Callback           *JobCallback           `json:"callback,omitempty"`
+	CreatedAt          time.Time              `json:"created_at"`
+	StartedAt          time.Time              `json:"started_at,omitzero"`
+	CompletedAt        time.Time              `json:"completed_at,omitzero"`
+}
+
+type JobError struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// API representation of a transcription job
+type TranscriptionJobResponse struct {
+	ID                 string                 `json:"id"`
+	Object             string                 `json:"object"`
+	Status             JobStatus              `json:"status"`
+	Model              string                 `json:"model"`
+	FileName           string                 `json:"file_name"`
+	FileSize           int64                  `json:"file_size"`
+	RequestID          string                 `json:"request_id"`
+	UpstreamRequestIDs []string               `json:"upstream_request_ids,omitempty"`
+	Result             *TranscriptionResponse `json:"result,omitempty"`
+	Error              *JobError              `json:"error,omitempty"`
+	Callback           *JobCallbackResponse   `json:"callback,omitempty"`
+	CreatedAt          int64                  `json:"created_at"`
+	StartedAt          int64                  `json:"started_at,omitempty"`
+	CompletedAt        int64                  `json:"completed_at,omitempty"`
+}
+
+// NewTranscriptionJobResponse converts a stored job into its API representation
+func NewTranscriptionJobResponse(job *TranscriptionJob) TranscriptionJobResponse {
+	response := TranscriptionJobResponse{
+		ID:                 job.ID,
+		Object:             "transcription.job",
+		Status:             job.Status,
+		Model:              job.Params.Model,
+		FileName:           job.FileName,
+		FileSize:           job.FileSize,
+		RequestID:          job.RequestID,
+		UpstreamRequestIDs: job.UpstreamRequestIDs,
+		Result:             job.Result,
+		Error:              job.Error,
+		CreatedAt:          job.CreatedAt.Unix(),
+	}
+	if !job.StartedAt.IsZero() {
+		response.StartedAt = job.StartedAt.Unix()
+	}
+	if !job.CompletedAt.IsZero() {
+		response.CompletedAt = job.CompletedAt.Unix()
+	}
+	if job.Callback != nil {
+		response.Callback = &JobCallbackResponse{
+			URL:            job.Callback.URL,
+			DeliveryID:     job.Callback.DeliveryID,
+			Status:         job.Callback.Status,
+			Attempts:       job.Callback.Attempts,
+			LastStatusCode: job.Callback.LastStatusCode,
+			LastError:      job.Callback.LastError,
+		}
+		if !job.Callback.DeliveredAt.IsZero() {
+			response.Callback.DeliveredAt = job.Callback.DeliveredAt.Unix()
+		}
+	}
+	return response
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Virtual API key managed through the admin API
+type VirtualKey struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	KeyPrefix     string    `json:"key_prefix"`
+	KeyHash       string    `json:"key_hash"`
+	UpstreamKey   string    `json:"upstream_key"`
+	AllowedModels []string  `json:"allowed_models,omitempty"`
+	Limits        KeyLimits `json:"limits"`
+	CallbackURL   string    `json:"callback_url,omitempty"`
+	// VocabularyIDs are added to the context of requests that set no vocabulary_id
+	VocabularyIDs []string `json:"vocabulary_ids,omitempty"`
+	// DictionaryIDs are applied to the transcripts of requests that set no dictionary_id
+	DictionaryIDs []string  `json:"dictionary_ids,omitempty"`
+	Disabled      bool      `json:"disabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	RotatedAt     time.Time `json:"rotated_at,omitzero"`
+}
+
+// KeyLimits holds per-key limits. Zero values mean unlimited.
+type KeyLimits struct {
+	RequestsPerMinute int   `json:"requests_per_minute,omitempty"`
+	MaxFileSize       int64 `json:"max_file_size,omitempty"`
+	// MaxDurationSeconds bounds the audio duration stated by the file headers
+	MaxDurationSeconds int `json:"max_duration_seconds,omitempty"`
+}
+
+// AllowsModel reports whether the key may be used with the given model
+func (k *VirtualKey) AllowsModel(model string) bool {
+	if len(k.AllowedModels) == 0 {
+		return true
+	}
+	for _, allowed := range k.AllowedModels {
+		if allowed == model {
+			return true
+		}
+	}
+	return false
+}
+
+// Admin API request to create a virtual key
+type CreateVirtualKeyRequest struct {
+	Name          string    `json:"name" binding:"required"`
+	UpstreamKey   string    `json:"upstream_key" binding:"required"`
+	AllowedModels []string  `json:"allowed_models"`
+	Limits        KeyLimits `json:"limits"`
+	CallbackURL   string    `json:"callback_url"`
+	VocabularyIDs []string  `json:"vocabulary_ids"`
+	DictionaryIDs []string  `json:"dictionary_ids"`
+}
+
+// Admin API request to update a virtual key. Nil fields are left unchanged.
+type UpdateVirtualKeyRequest struct {
+	Name          *string    `json:"name"`
+	UpstreamKey   *string    `json:"upstream_key"`
+	AllowedModels *[]string  `json:"allowed_models"`
+	Limits        *KeyLimits `json:"limits"`
+	CallbackURL   *string    `json:"callback_url"`
+	VocabularyIDs *[]string  `json:"vocabulary_ids"`
+	DictionaryIDs *[]string  `json:"dictionary_ids"`
+	Disabled      *bool      `json:"disabled"`
+}
+
+// Admin API representation of a virtual key. The secret is only set on create and rotate.
+type VirtualKeyResponse struct {
+	ID            string    `json:"id"`
+	Object        string    `json:"object"`
+	Name          string    `json:"name"`
+	Key           string    `json:"key,omitempty"`
+	KeyPrefix     string    `json:"key_prefix"`
+	UpstreamKey   string    `json:"upstream_key"`
+	AllowedModels []string  `json:"allowed_models"`
+	Limits        KeyLimits `json:"limits"`
+	CallbackURL   string    `json:"callback_url,omitempty"`
+	VocabularyIDs []string  `json:"vocabulary_ids"`
+	DictionaryIDs []string  `json:"dictionary_ids"`
+	Disabled      bool      `json:"disabled"`
+	CreatedAt     int64     `json:"created_at"`
+	UpdatedAt     int64     `json:"updated_at"`
+	RotatedAt     int64     `json:"rotated_at,omitempty"`
+}
+
+type VirtualKeyListResponse struct {
+	Object string               `json:"object"`
+	Data   []VirtualKeyResponse `json:"data"`
+}
@@ -0,0 +1,95 @@
+package models
+
+// DashScope asynchronous file transcription request (Paraformer)
+type FileTranscriptionRequest struct {
+	Model      string                      `json:"model"`
+	Input      FileTranscriptionInput      `json:"input"`
+	Parameters FileTranscriptionParameters `json:"parameters"`
+}
+
+type FileTranscriptionInput struct {
+	FileURLs []string `json:"file_urls"`
+}
+
+type FileTranscriptionParameters struct {
+	DiarizationEnabled bool `json:"diarization_enabled"`
+	// SpeakerCount is a hint for the number of speakers, 0 to let the model decide
+	SpeakerCount  int      `json:"speaker_count,omitempty"`
+	LanguageHints []string `json:"language_hints,omitempty"`
+}
+
+// Status of a DashScope asynchronous task
+type TaskStatus string
+
+const (
+	TaskStatusPending   TaskStatus = "PENDING"
+	TaskStatusRunning   TaskStatus = "RUNNING"
+	TaskStatusSucceeded TaskStatus = "SUCCEEDED"
+	TaskStatusFailed    TaskStatus = "FAILED"
+	TaskStatusCanceled  TaskStatus = "CANCELED"
+	TaskStatusUnknown   TaskStatus = "UNKNOWN"
+)
+
+// IsTerminal reports whether a task in this status will not change anymore
+func (s TaskStatus) IsTerminal() bool {
+	return s == TaskStatusSucceeded || s == TaskStatusFailed || s == TaskStatusCanceled || s == TaskStatusUnknown
+}
+
+// DashScope asynchronous task, as returned when it is submitted and when it is polled
+type TaskResponse struct {
+	RequestID string     `json:"request_id"`
+	Output    TaskOutput `json:"output"`
+	Usage     *TaskUsage `json:"usage,omitempty"`
+}
+
+type TaskOutput struct {
+	TaskID     string       `json:"task_id"`
+	TaskStatus TaskStatus   `json:"task_status"`
+	Code       string       `json:"code,omitempty"`
+	Message    string       `json:"message,omitempty"`
+	Results    []TaskResult `json:"results,omitempty"`
+}
+
+// Result of one file of a file transcription task
+type TaskResult struct {
+	FileURL string `json:"file_url"`
+	// TranscriptionURL is a presigned URL of the transcription of the file
+	TranscriptionURL string     `json:"transcription_url,omitempty"`
+	SubtaskStatus    TaskStatus `json:"subtask_status"`
+	Code             string     `json:"code,omitempty"`
+	Message          string     `json:"message,omitempty"`
+}
+
+type TaskUsage struct {
+	// Duration is the billed audio in seconds
+	Duration float64 `json:"duration"`
+}
+
+// Transcription of one file, fetched from the transcription URL of a task result
+type FileTranscription struct {
+	FileURL     string                      `json:"file_url"`
+	Properties  FileTranscriptionProperties `json:"properties"`
+	Transcripts []FileTranscript            `json:"transcripts"`
+}
+
+type FileTranscriptionProperties struct {
+	AudioFormat      string `json:"audio_format"`
+	OriginalDuration int64  `json:"original_duration_in_milliseconds"`
+}
+
+// Transcript of one channel of a file
+type FileTranscript struct {
+	ChannelID int                  `json:"channel_id"`
+	Text      string               `json:"text"`
+	Sentences []TranscriptSentence `json:"sentences"`
+}
+
+// Sentence of a transcript with its offsets in milliseconds
+type TranscriptSentence struct {
+	BeginTime  int64  `json:"begin_time"`
+	EndTime    int64  `json:"end_time"`
+	Text       string `json:"text"`
+	SentenceID int    `json:"sentence_id"`
+	// SpeakerID is set when diarization is enabled, counted from 0
+	SpeakerID *int `json:"speaker_id,omitempty"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Supported languages
 type SupportedLanguage string
@@ -53,6 +56,25 @@ const (
 	EmotionFearful   EmotionType = "fearful"
 )
 
+var EmotionTypes = []EmotionType{
+	EmotionSurprised,
+	EmotionNeutral,
+	EmotionHappy,
+	EmotionSad,
+	EmotionDisgusted,
+	EmotionAngry,
+	EmotionFearful,
+}
+
+func IsValidEmotion(emotion string) bool {
+	for _, supported := range EmotionTypes {
+		if string(supported) == emotion {
+			return true
+		}
+	}
+	return false
+}
+
 // DashScope ASR request
 type ASRRequest struct {
 	Model      string        `json:"model"`
@@ -95,6 +117,15 @@ type ASRResponse struct {
 	Output  ASROutput `json:"output"`
 	Usage   ASRUsage  `json:"usage"`
 	Request string    `json:"request_id"`
+	// Served lists the models and providers that produced the response, more than one
+	// when chunks were served by different fallbacks
+	Served []ServedBy `json:"-"`
+}
+
+// ServedBy is a model that served a transcription and the provider it was routed to
+type ServedBy struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
 }
 
 type ASROutput struct {
@@ -120,9 +151,17 @@ type ASROutputTokensDetails struct {
 	TextTokens int `json:"text_tokens"`
 }
 
+// DashScope error response
+type DashScopeErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
+
 // Upload policy response
 type UploadPolicyResponse struct {
-	Data UploadPolicyData `json:"data"`
+	Data      UploadPolicyData `json:"data"`
+	RequestID string           `json:"request_id"`
 }
 
 type UploadPolicyData struct {
@@ -140,17 +179,201 @@ type UploadResult struct {
 	OSSURL     string    `json:"oss_url"`
 	ExpireTime time.Time `json:"expire_time"`
 	ModelUsed  string    `json:"model_used"`
+	// SilenceTrimmed is the seconds of silence removed from the audio before upload
+	SilenceTrimmed float64 `json:"silence_trimmed,omitempty"`
+	// Audio is the uploaded file as probed before upload, nil when it could not be probed
+	Audio *AudioInfo `json:"audio,omitempty"`
+}
+
+// Audio stream of an uploaded file as stated by its container headers
+type AudioInfo struct {
+	ContentType string  `json:"content_type"`
+	Duration    float64 `json:"duration"`
+	SampleRate  int     `json:"sample_rate"`
+	Channels    int     `json:"channels"`
+	Bitrate     int     `json:"bitrate"`
+}
+
+// Info describes the upload in a transcription response, or returns nil when the audio
+// was sent to the provider inline
+func (r *UploadResult) Info() *UploadInfo {
+	if r.OSSURL == "" {
+		return nil
+	}
+	return &UploadInfo{
+		OSSURL:         r.OSSURL,
+		ExpireTime:     r.ExpireTime.Format(time.RFC3339),
+		ModelUsed:      r.ModelUsed,
+		SilenceTrimmed: r.SilenceTrimmed,
+	}
+}
+
+// Response formats of the transcription endpoints
+type ResponseFormat string
+
+const (
+	ResponseFormatJSON        ResponseFormat = "json"
+	ResponseFormatText        ResponseFormat = "text"
+	ResponseFormatVerboseJSON ResponseFormat = "verbose_json"
+	ResponseFormatSRT         ResponseFormat = "srt"
+	ResponseFormatVTT         ResponseFormat = "vtt"
+)
+
+func IsValidResponseFormat(format string) bool {
+	switch ResponseFormat(format) {
+	case ResponseFormatJSON, ResponseFormatText, ResponseFormatVerboseJSON, ResponseFormatSRT, ResponseFormatVTT:
+		return true
+	}
+	return false
+}
+
+// HasSegments reports whether the format carries segment timestamps
+func (f ResponseFormat) HasSegments() bool {
+	return f == ResponseFormatVerboseJSON || f == ResponseFormatSRT || f == ResponseFormatVTT
+}
+
+// How the channels of multi-channel audio are transcribed
+type ChannelMode string
+
+const (
+	// ChannelModeMixed transcribes the channels mixed down to one
+	ChannelModeMixed ChannelMode = "mixed"
+	// ChannelModeSplit transcribes every channel separately
+	ChannelModeSplit ChannelMode = "split"
+)
+
+func IsValidChannelMode(mode string) bool {
+	return ChannelMode(mode) == ChannelModeMixed || ChannelMode(mode) == ChannelModeSplit
+}
+
+// Chinese scripts transcripts can be converted to
+type OutputScript string
+
+const (
+	// OutputScriptHant is Traditional Chinese
+	OutputScriptHant OutputScript = "hant"
+	// OutputScriptHans is Simplified Chinese
+	OutputScriptHans OutputScript = "hans"
+)
+
+func IsValidOutputScript(script string) bool {
+	return OutputScript(script) == OutputScriptHant || OutputScript(script) == OutputScriptHans
+}
+
+// IsChinese reports whether the language is written in Chinese characters
+func (l SupportedLanguage) IsChinese() bool {
+	return l == LanguageZh || l == LanguageYue
+}
+
+// Optional fields of transcription responses, requested with include[]
+type ResponseInclude string
+
+const (
+	// IncludeEmotion adds the emotion detected in the speech
+	IncludeEmotion ResponseInclude = "emotion"
+	// IncludeAnnotations adds the annotations of the model, one per transcribed chunk
+	IncludeAnnotations ResponseInclude = "annotations"
+)
+
+func IsValidResponseInclude(include string) bool {
+	return ResponseInclude(include) == IncludeEmotion || ResponseInclude(include) == IncludeAnnotations
+}
+
+// Kinds of text masked in transcripts
+type RedactionCategory string
+
+const (
+	RedactionProfanity  RedactionCategory = "profanity"
+	RedactionPhone      RedactionCategory = "phone"
+	RedactionEmail      RedactionCategory = "email"
+	RedactionIDNumber   RedactionCategory = "id_number"
+	RedactionCreditCard RedactionCategory = "credit_card"
+)
+
+// RedactionCategories lists the redaction categories in the order they are applied
+var RedactionCategories = []RedactionCategory{
+	RedactionEmail,
+	RedactionIDNumber,
+	RedactionCreditCard,
+	RedactionPhone,
+	RedactionProfanity,
+}
+
+func IsValidRedactionCategory(category string) bool {
+	for _, supported := range RedactionCategories {
+		if string(supported) == category {
+			return true
+		}
+	}
+	return false
 }
 
 // OpenAI compatible transcription response
 type TranscriptionResponse struct {
-	Text           string              `json:"text"`
-	Task           string              `json:"task"`
-	Language       string              `json:"language"`
-	Duration       float64             `json:"duration,omitempty"`
-	Words          []TranscriptionWord `json:"words,omitempty"`
-	UploadInfo     *UploadInfo         `json:"upload_info,omitempty"`
-	ProcessingTime int64               `json:"processing_time_ms,omitempty"`
+	Text           string                 `json:"text"`
+	Task           string                 `json:"task"`
+	Language       string                 `json:"language"`
+	Duration       float64                `json:"duration,omitempty"`
+	Segments       []TranscriptionSegment `json:"segments,omitempty"`
+	Words          []TranscriptionWord    `json:"words,omitempty"`
+	Channels       []ChannelTranscript    `json:"channels,omitempty"`
+	UploadInfo     *UploadInfo            `json:"upload_info,omitempty"`
+	ProcessingTime int64                  `json:"processing_time_ms,omitempty"`
+	// Replacements lists the replacement rules that changed the text, with verbose_json
+	Replacements []ReplacementHit `json:"replacements,omitempty"`
+	// Redactions counts the masked matches in the text by category, with verbose_json
+	Redactions map[RedactionCategory]int `json:"redactions,omitempty"`
+	// Emotion and Annotations are returned when requested with include[]
+	Emotion     EmotionType               `json:"emotion,omitempty"`
+	Annotations []TranscriptionAnnotation `json:"annotations,omitempty"`
+}
+
+// TranscriptionAnnotation is what the model reported about the speech of a transcribed
+// request
+type TranscriptionAnnotation struct {
+	Type     string            `json:"type"`
+	Language SupportedLanguage `json:"language,omitempty"`
+	Emotion  EmotionType       `json:"emotion,omitempty"`
+}
+
+// ReplacementHit reports how often a rule of a replacement dictionary matched the text
+type ReplacementHit struct {
+	Dictionary string `json:"dictionary"`
+	// Rule is the position of the rule in the dictionary, counted from 0
+	Rule  int    `json:"rule"`
+	Find  string `json:"find"`
+	Count int    `json:"count"`
+}
+
+// TranscriptionSegment is a span of the transcript with its offsets in seconds
+type TranscriptionSegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+	// Channel is the channel the segment was transcribed from, counted from 1, with
+	// channel_mode=split
+	Channel int `json:"channel,omitempty"`
+	// Speaker labels the speaker of the segment with diarize=true
+	Speaker string `json:"speaker,omitempty"`
+}
+
+// Label returns who spoke the segment, its channel or speaker, or an empty string when
+// the transcript does not tell speakers apart
+func (s TranscriptionSegment) Label() string {
+	switch {
+	case s.Speaker != "":
+		return s.Speaker
+	case s.Channel > 0:
+		return fmt.Sprintf("Channel %d", s.Channel)
+	}
+	return ""
+}
+
+// Transcript of one channel of a recording transcribed with channel_mode=split
+type ChannelTranscript struct {
+	Channel int    `json:"channel"`
+	Text    string `json:"text"`
 }
 
 type TranscriptionWord struct {
@@ -160,9 +383,10 @@ type TranscriptionWord struct {
 }
 
 type UploadInfo struct {
-	OSSURL     string `json:"oss_url"`
-	ExpireTime string `json:"expire_time"`
-	ModelUsed  string `json:"model_used"`
+	OSSURL         string  `json:"oss_url"`
+	ExpireTime     string  `json:"expire_time"`
+	ModelUsed      string  `json:"model_used"`
+	SilenceTrimmed float64 `json:"silence_trimmed,omitempty"`
 }
 
 // Verbose transcription response (for detailed format)
@@ -171,6 +395,7 @@ type VerboseTranscriptionResponse struct {
 	RequestID   string       `json:"request_id"`
 	Timestamp   string       `json:"timestamp"`
 	ASRMetadata *ASRMetadata `json:"asr_metadata,omitempty"`
+	Audio       *AudioInfo   `json:"audio,omitempty"`
 }
 
 type ASRMetadata struct {
@@ -178,6 +403,9 @@ type ASRMetadata struct {
 	Emotion          string    `json:"emotion"`
 	FinishReason     string    `json:"finish_reason"`
 	Usage            UsageInfo `json:"usage"`
+	// ServedBy is the models and providers that transcribed the audio, which differ from
+	// the requested model after a fallback
+	ServedBy []ServedBy `json:"served_by,omitempty"`
 }
 
 type UsageInfo struct {
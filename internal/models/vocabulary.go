@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Vocabulary sources
+const (
+	// VocabularySourceConfig is a vocabulary defined in the configuration, which cannot be
+	// changed through the admin API
+	VocabularySourceConfig = "config"
+	VocabularySourceAPI    = "api"
+)
+
+// Vocabulary is a named list of terms, such as product names and jargon, added to the
+// context of the transcriptions that reference it
+type Vocabulary struct {
+	ID        string    `json:"id"`
+	Terms     []string  `json:"terms"`
+	Source    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsValidVocabularyID reports whether id can name a vocabulary: 1 to 64 lowercase
+// letters, digits, '-' and '_'
+func IsValidVocabularyID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Admin API request to create or replace a vocabulary
+type PutVocabularyRequest struct {
+	Terms []string `json:"terms" binding:"required"`
+}
+
+// Admin API representation of a vocabulary
+type VocabularyResponse struct {
+	ID        string   `json:"id"`
+	Object    string   `json:"object"`
+	Terms     []string `json:"terms"`
+	Source    string   `json:"source"`
+	CreatedAt int64    `json:"created_at,omitempty"`
+	UpdatedAt int64    `json:"updated_at,omitempty"`
+}
+
+type VocabularyListResponse struct {
+	Object string               `json:"object"`
+	Data   []VocabularyResponse `json:"data"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Callback delivery statuses
+type CallbackStatus string
+
+const (
+	CallbackStatusPending   CallbackStatus = "pending"
+	CallbackStatusDelivered CallbackStatus = "delivered"
+	CallbackStatusFailed    CallbackStatus = "failed"
+)
+
+// Webhook callback registered for a job
+type JobCallback struct {
+	URL            string         `json:"url"`
+	DeliveryID     string         `json:"delivery_id"`
+	Status         CallbackStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastStatusCode int            `json:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty"`
+	DeliveredAt    time.Time      `json:"delivered_at,omitzero"`
+}
+
+// API representation of a job callback
+type JobCallbackResponse struct {
+	URL            string         `json:"url"`
+	DeliveryID     string         `json:"delivery_id"`
+	Status         CallbackStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastStatusCode int            `json:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty"`
+	DeliveredAt    int64          `json:"delivered_at,omitempty"`
+}
+
+// Payload POSTed to a callback URL when a job finishes
+type WebhookEvent struct {
+	ID        string                   `json:"id"`
+	Object    string                   `json:"object"`
+	Type      string                   `json:"type"`
+	CreatedAt int64                    `json:"created_at"`
+	Data      TranscriptionJobResponse `json:"data"`
+}
+
+// Callback that could not be delivered after all retries
+type WebhookDeadLetter struct {
+	ID             string          `json:"id"`
+	JobID          string          `json:"job_id"`
+	URL            string          `json:"url"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+// Admin API representation of a dead-lettered callback
+type WebhookDeadLetterResponse struct {
+	ID             string          `json:"id"`
+	Object         string          `json:"object"`
+	JobID          string          `json:"job_id"`
+	URL            string          `json:"url"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      int64           `json:"created_at"`
+	FailedAt       int64           `json:"failed_at"`
+}
+
+type WebhookDeadLetterListResponse struct {
+	Object string                      `json:"object"`
+	Data   []WebhookDeadLetterResponse `json:"data"`
+}
@@ -0,0 +1,24 @@
+package requestid
+
+import "context"
+
+type callerKey struct{}
+
+// Caller identifies on whose behalf a request dispatched in-process, such as a batch
+// line, is made
+type Caller struct {
+	// APIKey is the pass-through upstream key, used when VirtualKeyID is empty
+	APIKey       string
+	VirtualKeyID string
+}
+
+// WithCaller returns a copy of ctx carrying the caller of an in-process request
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller stored in ctx, if any
+func CallerFromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
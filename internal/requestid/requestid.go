@@ -0,0 +1,103 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// Header names used for request correlation
+const (
+	Header         = "X-Request-ID"
+	UpstreamHeader = "X-DashScope-Request-ID"
+)
+
+// Maximum accepted length of a caller-provided request ID
+const maxLength = 128
+
+type idKey struct{}
+type upstreamKey struct{}
+
+// Upstream collects the DashScope request IDs seen while serving one request
+type Upstream struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+// IDs returns the recorded upstream request IDs in call order
+func (u *Upstream) IDs() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return append([]string(nil), u.ids...)
+}
+
+// String returns the recorded upstream request IDs as a comma-separated list
+func (u *Upstream) String() string {
+	return strings.Join(u.IDs(), ", ")
+}
+
+func (u *Upstream) add(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, existing := range u.ids {
+		if existing == id {
+			return
+		}
+	}
+	u.ids = append(u.ids, id)
+}
+
+// New generates a random request ID
+func New() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Valid reports whether a caller-provided request ID is safe to accept and echo back
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithID returns a copy of ctx carrying the request ID and a fresh upstream ID collector
+func WithID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, idKey{}, id)
+	return context.WithValue(ctx, upstreamKey{}, &Upstream{})
+}
+
+// FromContext returns the request ID stored in ctx
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// UpstreamFromContext returns the upstream ID collector stored in ctx, if any
+func UpstreamFromContext(ctx context.Context) *Upstream {
+	upstream, _ := ctx.Value(upstreamKey{}).(*Upstream)
+	return upstream
+}
+
+// RecordUpstream records a DashScope request ID for the request in ctx
+func RecordUpstream(ctx context.Context, id string) {
+	if id == "" {
+		return
+	}
+	if upstream := UpstreamFromContext(ctx); upstream != nil {
+		upstream.add(id)
+	}
+}
@@ -2,25 +2,101 @@ package services
 
 import (
 	"context"
-	"time"
+	"fmt"
+	"io"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"qwen3-compatibility/internal/metrics"
 	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/tracing"
 	"qwen3-compatibility/pkg/client"
 )
 
 type ASRService struct {
-	client client.ASRProvider
+	providers *ProviderRegistry
+	// enableITN is used for requests that do not set enable_itn
+	enableITN bool
 }
 
-func NewASRService(client client.ASRProvider) *ASRService {
+func NewASRService(providers *ProviderRegistry, enableITN bool) *ASRService {
 	return &ASRService{
-		client: client,
+		providers: providers,
+		enableITN: enableITN,
 	}
 }
 
-// TranscribeAudio transcribes audio using DashScope ASR service
-func (s *ASRService) TranscribeAudio(ctx context.Context, apiKey, audioURL, model string, language *models.SupportedLanguage, prompt string) (*models.ASRResponse, error) {
-	return s.client.CallASR(ctx, apiKey, audioURL, model, language, true, prompt)
+// NeedsUpload reports whether audio for a model is uploaded to OSS and transcribed by URL
+// with TranscribeAudio, rather than sent inline with TranscribeInline
+func (s *ASRService) NeedsUpload(model string) bool {
+	return s.providers.Lookup(model).NeedsUpload()
+}
+
+// Fallbacks returns the models tried in order when transcription with model fails
+func (s *ASRService) Fallbacks(model string) []string {
+	return s.providers.Fallbacks(model)
+}
+
+// FallbackClass returns the class of a transcription error when it falls back to the next
+// model, or an empty string when it does not
+func (s *ASRService) FallbackClass(err error) string {
+	return s.providers.FallbackClass(err)
+}
+
+// TranscribeAudio transcribes uploaded audio with the provider of the model. enableITN
+// converts spoken numbers to written form; nil uses the server default.
+func (s *ASRService) TranscribeAudio(ctx context.Context, apiKey, audioURL, model string, language *models.SupportedLanguage, enableITN *bool, prompt string) (*models.ASRResponse, error) {
+	provider := s.providers.Lookup(model)
+	asrClient, ok := provider.Backend.(client.ASRProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s of model %s does not read uploaded audio", provider.Name, model)
+	}
+
+	itn := s.enableITN
+	if enableITN != nil {
+		itn = *enableITN
+	}
+
+	done := metrics.UpstreamStarted(metrics.PhaseASR)
+	ctx, span := tracing.Start(ctx, "asr.call", attribute.String("asr.model", model), attribute.String("asr.provider", provider.Name), attribute.Bool("asr.enable_itn", itn))
+	asrResponse, err := asrClient.CallASR(ctx, apiKey, audioURL, provider.UpstreamModel(model), language, itn, prompt)
+	tracing.End(span, err)
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+
+	if asrResponse.Usage.Seconds != nil {
+		metrics.AddAudioSeconds(model, *asrResponse.Usage.Seconds)
+	}
+	asrResponse.Served = []models.ServedBy{{Model: model, Provider: provider.Name}}
+
+	return asrResponse, nil
+}
+
+// TranscribeInline transcribes audio sent in the request with the provider of the model
+func (s *ASRService) TranscribeInline(ctx context.Context, audio io.Reader, fileName, contentType, model string, language *models.SupportedLanguage, prompt string) (*models.ASRResponse, error) {
+	provider := s.providers.Lookup(model)
+	inlineClient, ok := provider.Backend.(client.InlineASRProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s of model %s does not take inline audio", provider.Name, model)
+	}
+
+	done := metrics.UpstreamStarted(metrics.PhaseASR)
+	ctx, span := tracing.Start(ctx, "asr.call", attribute.String("asr.model", model), attribute.String("asr.provider", provider.Name))
+	asrResponse, err := inlineClient.TranscribeInline(ctx, audio, fileName, contentType, provider.UpstreamModel(model), language, prompt)
+	tracing.End(span, err)
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+
+	if asrResponse.Usage.Seconds != nil {
+		metrics.AddAudioSeconds(model, *asrResponse.Usage.Seconds)
+	}
+	asrResponse.Served = []models.ServedBy{{Model: model, Provider: provider.Name}}
+
+	return asrResponse, nil
 }
 
 // ConvertToOpenAIFormat converts ASR response to OpenAI compatible format
@@ -35,17 +111,8 @@ func (s *ASRService) ConvertToOpenAIFormat(asrResponse *models.ASRResponse, proc
 	}
 
 	choice := asrResponse.Output.Choices[0]
-	var detectedLanguage string
 	var duration float64
 
-	// Extract language and emotion from annotations if available
-	if len(choice.Message.Content) > 0 && len(choice.Message.Content[0].Text) > 0 {
-		// Get detected language from annotations
-		if len(choice.Message.Annotations) > 0 {
-			detectedLanguage = string(choice.Message.Annotations[0].Language)
-		}
-	}
-
 	// Get duration from usage
 	if asrResponse.Usage.Seconds != nil {
 		duration = *asrResponse.Usage.Seconds
@@ -59,12 +126,55 @@ func (s *ASRService) ConvertToOpenAIFormat(asrResponse *models.ASRResponse, proc
 	return &models.TranscriptionResponse{
 		Text:           text,
 		Task:           "transcribe",
-		Language:       detectedLanguage,
+		Language:       string(annotationLanguage(choice.Message.Annotations)),
 		Duration:       duration,
 		ProcessingTime: processingTimeMs,
+		Emotion:        annotationEmotion(choice.Message.Annotations),
+		Annotations:    transcriptionAnnotations(choice.Message.Annotations),
 	}
 }
 
+// annotationLanguage returns the first language reported in the annotations. Annotations
+// of chunks without speech, or of other types, may not report one.
+func annotationLanguage(annotations []models.ASRAnnotation) models.SupportedLanguage {
+	for _, annotation := range annotations {
+		if annotation.Language != "" {
+			return annotation.Language
+		}
+	}
+	return ""
+}
+
+// annotationEmotion returns the first known emotion reported in the annotations
+func annotationEmotion(annotations []models.ASRAnnotation) models.EmotionType {
+	for _, annotation := range annotations {
+		if models.IsValidEmotion(string(annotation.Emotion)) {
+			return annotation.Emotion
+		}
+	}
+	return ""
+}
+
+// transcriptionAnnotations converts the annotations for the response, leaving out
+// emotions that are not known emotion types
+func transcriptionAnnotations(annotations []models.ASRAnnotation) []models.TranscriptionAnnotation {
+	if len(annotations) == 0 {
+		return nil
+	}
+	converted := make([]models.TranscriptionAnnotation, 0, len(annotations))
+	for _, annotation := range annotations {
+		item := models.TranscriptionAnnotation{
+			Type:     annotation.Type,
+			Language: annotation.Language,
+		}
+		if models.IsValidEmotion(string(annotation.Emotion)) {
+			item.Emotion = annotation.Emotion
+		}
+		converted = append(converted, item)
+	}
+	return converted
+}
+
 // CreateVerboseResponse creates a detailed response with metadata
 func (s *ASRService) CreateVerboseResponse(asrResponse *models.ASRResponse, processingTimeMs int64, uploadInfo *models.UploadResult) *models.VerboseTranscriptionResponse {
 	baseResponse := s.ConvertToOpenAIFormat(asrResponse, processingTimeMs)
@@ -77,24 +187,24 @@ func (s *ASRService) CreateVerboseResponse(asrResponse *models.ASRResponse, proc
 
 	// Add upload info if available
 	if uploadInfo != nil {
-		verboseResponse.UploadInfo = &models.UploadInfo{
-			OSSURL:     uploadInfo.OSSURL,
-			ExpireTime: uploadInfo.ExpireTime.Format(time.RFC3339),
-			ModelUsed:  uploadInfo.ModelUsed,
-		}
+		verboseResponse.UploadInfo = uploadInfo.Info()
 	}
 
-	// Add ASR metadata if available
+	// Add ASR metadata if available. Responses without annotations, such as those of
+	// inline providers, still report who served them.
 	if len(asrResponse.Output.Choices) > 0 {
 		choice := asrResponse.Output.Choices[0]
+		metadata := &models.ASRMetadata{
+			FinishReason: choice.FinishReason,
+			Usage:        s.convertUsageInfo(asrResponse.Usage),
+			ServedBy:     asrResponse.Served,
+		}
 		if len(choice.Message.Annotations) > 0 {
-			annotation := choice.Message.Annotations[0]
-			verboseResponse.ASRMetadata = &models.ASRMetadata{
-				DetectedLanguage: string(annotation.Language),
-				Emotion:          string(annotation.Emotion),
-				FinishReason:     choice.FinishReason,
-				Usage:            s.convertUsageInfo(asrResponse.Usage),
-			}
+			metadata.DetectedLanguage = string(annotationLanguage(choice.Message.Annotations))
+			metadata.Emotion = string(annotationEmotion(choice.Message.Annotations))
+		}
+		if len(choice.Message.Annotations) > 0 || len(asrResponse.Served) > 0 {
+			verboseResponse.ASRMetadata = metadata
 		}
 	}
 
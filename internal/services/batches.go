@@ -0,0 +1,846 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+	"qwen3-compatibility/internal/store"
+)
+
+var (
+	ErrBatchNotFound  = stderrors.New("batch not found")
+	ErrBatchQueueFull = stderrors.New("batch queue is full")
+	ErrBatchFinished  = stderrors.New("batch already finished")
+)
+
+const (
+	// Only the OpenAI completion window is supported
+	batchCompletionWindow = "24h"
+	// Maximum size of one line of a batch input file
+	maxBatchLineSize = 1024 * 1024
+	// Maximum number of validation errors reported for an input file
+	maxBatchErrors = 100
+	// Names of the result files kept while a batch is in progress
+	batchOutputResults = "output"
+	batchErrorResults  = "errors"
+)
+
+// batchEndpoint describes how batch lines are dispatched to an endpoint
+type batchEndpoint struct {
+	// multipart endpoints receive the line body as form fields
+	multipart bool
+}
+
+// Endpoints that batch lines may target
+var batchEndpoints = map[string]batchEndpoint{
+	"/v1/audio/transcriptions": {multipart: true},
+}
+
+// BatchService runs OpenAI-compatible batches. Each input line is dispatched in-process
+// to handler, which serves the same routes as the public API.
+type BatchService struct {
+	store   *store.BatchStore
+	files   *store.FileStore
+	handler http.Handler
+	config  *config.BatchesConfig
+
+	queue  chan string
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	active map[string]context.CancelFunc
+}
+
+func NewBatchService(batchStore *store.BatchStore, fileStore *store.FileStore, handler http.Handler, batchesConfig *config.BatchesConfig) *BatchService {
+	ctx, stop := context.WithCancel(context.Background())
+	return &BatchService{
+		store:   batchStore,
+		files:   fileStore,
+		handler: handler,
+		config:  batchesConfig,
+		queue:   make(chan string, batchesConfig.QueueSize),
+		ctx:     ctx,
+		stop:    stop,
+		active:  make(map[string]context.CancelFunc),
+	}
+}
+
+// Start launches the workers and re-queues batches left unfinished by a previous run
+func (s *BatchService) Start() {
+	for i := 0; i < s.config.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	// The store lists batches newest first; resume them in the order they were created
+	batches := s.store.List()
+	slices.Reverse(batches)
+
+	var pending []string
+	for _, batch := range batches {
+		switch {
+		case !batch.Status.IsTerminal():
+			pending = append(pending, batch.ID)
+		case batch.APIKey != "":
+			// Finished by a version that kept the upstream key
+			_, _ = s.store.Update(batch.ID, func(batch *models.Batch) {
+				batch.APIKey = ""
+			})
+		}
+	}
+	if len(pending) > 0 {
+		slog.Info("Resuming pending batches", "count", len(pending))
+		s.wg.Add(1)
+		go s.requeue(pending)
+	}
+}
+
+// Stop stops the workers. Interrupted batches keep their results and resume on the next start.
+func (s *BatchService) Stop(ctx context.Context) error {
+	s.stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Create validates a batch request, persists the batch and queues it for processing
+func (s *BatchService) Create(ctx context.Context, req *models.CreateBatchRequest, owner string, caller requestid.Caller) (*models.Batch, error) {
+	if _, ok := batchEndpoints[req.Endpoint]; !ok {
+		return nil, errors.NewValidationError(fmt.Sprintf("Unsupported endpoint %q. Supported endpoints: %s", req.Endpoint, strings.Join(supportedBatchEndpoints(), ", ")))
+	}
+	if req.CompletionWindow != batchCompletionWindow {
+		return nil, errors.NewValidationError("completion_window must be " + batchCompletionWindow)
+	}
+
+	inputFile, err := s.files.Get(req.InputFileID)
+	if err != nil || inputFile.Owner != owner {
+		return nil, errors.NewValidationError("Input file not found")
+	}
+	if inputFile.Purpose != models.FilePurposeBatch {
+		return nil, errors.NewValidationError("Input file must have purpose " + models.FilePurposeBatch)
+	}
+
+	id, err := s.store.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	batch := &models.Batch{
+		ID:               id,
+		Endpoint:         req.Endpoint,
+		InputFileID:      req.InputFileID,
+		CompletionWindow: req.CompletionWindow,
+		Status:           models.BatchStatusValidating,
+		Metadata:         req.Metadata,
+		Owner:            owner,
+		VirtualKeyID:     caller.VirtualKeyID,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(24 * time.Hour),
+	}
+	// Virtual keys are resolved when lines run, so only pass-through keys are persisted,
+	// until the batch finishes
+	if caller.VirtualKeyID == "" {
+		batch.APIKey = caller.APIKey
+	}
+
+	if err := s.store.Create(batch); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.queue <- batch.ID:
+	default:
+		_, _ = s.store.Update(batch.ID, func(batch *models.Batch) {
+			batch.Status = models.BatchStatusFailed
+			batch.Errors = []models.BatchError{{Code: "queue_full", Message: "Batch queue is full"}}
+			batch.FailedAt = time.Now().UTC()
+			batch.APIKey = ""
+		})
+		return nil, ErrBatchQueueFull
+	}
+
+	logging.FromContext(ctx).Info("Batch created", "batch_id", batch.ID, "input_file_id", batch.InputFileID)
+
+	return batch, nil
+}
+
+// Get returns the batch with the given ID if it belongs to owner
+func (s *BatchService) Get(id, owner string) (*models.Batch, error) {
+	batch, err := s.store.Get(id)
+	if err != nil || batch.Owner != owner {
+		return nil, ErrBatchNotFound
+	}
+	return batch, nil
+}
+
+// List returns the batches of owner, newest first
+func (s *BatchService) List(owner string) []models.Batch {
+	var batches []models.Batch
+	for _, batch := range s.store.List() {
+		if batch.Owner == owner {
+			batches = append(batches, batch)
+		}
+	}
+	return batches
+}
+
+// Cancel stops a batch that belongs to owner. Results produced so far are kept.
+func (s *BatchService) Cancel(id, owner string) (*models.Batch, error) {
+	if _, err := s.Get(id, owner); err != nil {
+		return nil, err
+	}
+
+	var alreadyFinished bool
+	batch, err := s.store.Update(id, func(batch *models.Batch) {
+		if batch.Status.IsTerminal() || batch.Status == models.BatchStatusFinalizing {
+			alreadyFinished = true
+			return
+		}
+		if batch.Status != models.BatchStatusCancelling {
+			batch.Status = models.BatchStatusCancelling
+			batch.CancellingAt = time.Now().UTC()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if alreadyFinished {
+		return batch, ErrBatchFinished
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.active[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	return batch, nil
+}
+
+func (s *BatchService) worker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case id := <-s.queue:
+			s.process(id)
+		}
+	}
+}
+
+// requeue feeds batches left over from a previous run into the queue
+func (s *BatchService) requeue(ids []string) {
+	defer s.wg.Done()
+
+	for _, id := range ids {
+		select {
+		case s.queue <- id:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *BatchService) process(id string) {
+	batch, err := s.store.Get(id)
+	if err != nil || batch.Status.IsTerminal() {
+		return
+	}
+
+	logger := slog.Default().With("batch_id", id)
+
+	ctx, cancel := context.WithDeadline(s.ctx, batch.ExpiresAt)
+	defer cancel()
+
+	s.mu.Lock()
+	s.active[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, id)
+		s.mu.Unlock()
+	}()
+
+	lines, batchErrors, err := s.readInput(batch)
+	if err != nil || len(batchErrors) > 0 {
+		if err != nil {
+			batchErrors = []models.BatchError{{Code: "invalid_input_file", Message: err.Error()}}
+		}
+		_, _ = s.store.Update(id, func(batch *models.Batch) {
+			batch.Status = models.BatchStatusFailed
+			batch.Errors = batchErrors
+			batch.FailedAt = time.Now().UTC()
+			batch.APIKey = ""
+		})
+		logger.Warn("Batch input file is invalid", "errors", len(batchErrors))
+		return
+	}
+
+	batch, err = s.store.Update(id, func(batch *models.Batch) {
+		batch.RequestCounts.Total = len(lines)
+		if batch.Status == models.BatchStatusValidating {
+			batch.Status = models.BatchStatusInProgress
+			batch.InProgressAt = time.Now().UTC()
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to update batch", "error", err)
+		return
+	}
+
+	if batch.Status == models.BatchStatusInProgress {
+		logger.Info("Batch started", "requests", len(lines))
+		if err := s.run(ctx, batch, lines); err != nil {
+			logger.Error("Batch failed", "error", err)
+			_, _ = s.store.Update(id, func(batch *models.Batch) {
+				batch.Status = models.BatchStatusFailed
+				batch.Errors = []models.BatchError{{Code: "server_error", Message: "Failed to process batch"}}
+				batch.FailedAt = time.Now().UTC()
+				batch.APIKey = ""
+			})
+			return
+		}
+	}
+
+	if s.ctx.Err() != nil {
+		// Interrupted by shutdown; resume on the next start
+		return
+	}
+
+	if err := s.finalize(id, lines); err != nil {
+		logger.Error("Failed to finalize batch", "error", err)
+		return
+	}
+
+	batch, _ = s.store.Get(id)
+	if batch != nil {
+		logger.Info("Batch finished", "status", batch.Status,
+			"completed", batch.RequestCounts.Completed, "failed", batch.RequestCounts.Failed)
+	}
+}
+
+// readInput parses and validates the batch input file
+func (s *BatchService) readInput(batch *models.Batch) ([]models.BatchInputLine, []models.BatchError, error) {
+	f, err := s.files.Open(s.ctx, batch.InputFileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("input file is no longer available")
+	}
+	defer func() { _ = f.Close() }()
+
+	var lines []models.BatchInputLine
+	var batchErrors []models.BatchError
+	addError := func(lineNumber int, param, message string) {
+		if len(batchErrors) < maxBatchErrors {
+			batchErrors = append(batchErrors, models.BatchError{
+				Code: "invalid_request", Message: message, Line: lineNumber, Param: param,
+			})
+		}
+	}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchLineSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var line models.BatchInputLine
+		if err := json.Unmarshal(raw, &line); err != nil {
+			addError(lineNumber, "", "Line is not valid JSON")
+			continue
+		}
+		switch {
+		case line.CustomID == "":
+			addError(lineNumber, "custom_id", "custom_id is required")
+		case seen[line.CustomID]:
+			addError(lineNumber, "custom_id", "custom_id must be unique within the batch")
+		case line.Method != http.MethodPost:
+			addError(lineNumber, "method", "method must be POST")
+		case line.URL != batch.Endpoint:
+			addError(lineNumber, "url", "url must match the batch endpoint "+batch.Endpoint)
+		case !isJSONObject(line.Body):
+			addError(lineNumber, "body", "body must be a JSON object")
+		default:
+			seen[line.CustomID] = true
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	if len(batchErrors) == 0 {
+		switch {
+		case len(lines) == 0:
+			batchErrors = append(batchErrors, models.BatchError{Code: "empty_file", Message: "Input file contains no requests"})
+		case len(lines) > s.config.MaxRequests:
+			batchErrors = append(batchErrors, models.BatchError{
+				Code:    "too_many_requests",
+				Message: fmt.Sprintf("Input file contains %d requests, the maximum is %d", len(lines), s.config.MaxRequests),
+			})
+		}
+	}
+
+	return lines, batchErrors, nil
+}
+
+// run dispatches the lines that have no result yet, recording results as they complete
+func (s *BatchService) run(ctx context.Context, batch *models.Batch, lines []models.BatchInputLine) error {
+	done, err := s.completedLines(batch.ID)
+	if err != nil {
+		return err
+	}
+
+	results, err := newBatchResults(s.store, batch.ID)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	caller := requestid.Caller{APIKey: batch.APIKey, VirtualKeyID: batch.VirtualKeyID}
+	sem := make(chan struct{}, s.config.Concurrency)
+	var wg sync.WaitGroup
+
+	for _, line := range lines {
+		if done[line.CustomID] {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(line models.BatchInputLine) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := s.dispatch(ctx, line, caller)
+			if ctx.Err() != nil {
+				// Aborted by cancellation, expiry or shutdown; the line is left unprocessed
+				return
+			}
+
+			succeeded := result.Error == nil && result.Response != nil &&
+				result.Response.StatusCode >= 200 && result.Response.StatusCode < 300
+			if err := results.Add(result, succeeded); err != nil {
+				slog.Error("Failed to record batch result", "batch_id", batch.ID, "custom_id", line.CustomID, "error", err)
+				return
+			}
+
+			_, _ = s.store.Update(batch.ID, func(batch *models.Batch) {
+				if succeeded {
+					batch.RequestCounts.Completed++
+				} else {
+					batch.RequestCounts.Failed++
+				}
+			})
+		}(line)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// dispatch serves one batch line through the in-process handler
+func (s *BatchService) dispatch(ctx context.Context, line models.BatchInputLine, caller requestid.Caller) models.BatchOutputLine {
+	result := models.BatchOutputLine{
+		ID:       "batch_req_" + requestid.New(),
+		CustomID: line.CustomID,
+	}
+
+	ctx = requestid.WithCaller(ctx, caller)
+	req, err := s.newLineRequest(ctx, line)
+	if err != nil {
+		result.Error = &models.BatchOutputError{Code: "invalid_request", Message: err.Error()}
+		return result
+	}
+	req.Header.Set(requestid.Header, result.ID)
+
+	resp := newLineResponse()
+	s.handler.ServeHTTP(resp, req)
+
+	body := resp.body.Bytes()
+	if !json.Valid(body) {
+		body, _ = json.Marshal(string(body))
+	}
+	result.Response = &models.BatchOutputResponse{
+		StatusCode: resp.status,
+		RequestID:  resp.header.Get(requestid.Header),
+		Body:       body,
+	}
+
+	return result
+}
+
+// lineResponse buffers the response to a batch line served in-process
+type lineResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newLineResponse() *lineResponse {
+	return &lineResponse{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *lineResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *lineResponse) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *lineResponse) Write(p []byte) (int, error) {
+	return r.body.Write(p)
+}
+
+// newLineRequest builds the HTTP request for a batch line
+func (s *BatchService) newLineRequest(ctx context.Context, line models.BatchInputLine) (*http.Request, error) {
+	if !batchEndpoints[line.URL].multipart {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, line.URL, bytes.NewReader(line.Body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line.Body, &fields); err != nil {
+		return nil, fmt.Errorf("body must be a JSON object")
+	}
+
+	// JSON cannot carry audio, so "file" names a file uploaded through the Files API and is
+	// passed on as file_id. The handler checks that the file belongs to the batch owner.
+	if raw, ok := fields["file"]; ok {
+		if _, exists := fields["file_id"]; exists {
+			return nil, fmt.Errorf("only one of file and file_id may be set")
+		}
+		fields["file_id"] = raw
+		delete(fields, "file")
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, name := range names {
+		values, err := formValues(fields[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+		for _, value := range values {
+			if err := form.WriteField(name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, line.URL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req, nil
+}
+
+// completedLines returns the custom IDs that already have a result from a previous run
+func (s *BatchService) completedLines(id string) (map[string]bool, error) {
+	done := make(map[string]bool)
+	for _, name := range []string{batchOutputResults, batchErrorResults} {
+		f, err := os.Open(s.store.ResultPath(id, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read batch results: %w", err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			var result models.BatchOutputLine
+			if json.Unmarshal(scanner.Bytes(), &result) == nil && result.CustomID != "" {
+				done[result.CustomID] = true
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read batch results: %w", err)
+		}
+	}
+	return done, nil
+}
+
+// finalize records unprocessed lines of expired batches, publishes the result files and
+// moves the batch to its final status
+func (s *BatchService) finalize(id string, lines []models.BatchInputLine) error {
+	batch, err := s.store.Get(id)
+	if err != nil {
+		return err
+	}
+
+	finalStatus := models.BatchStatusCompleted
+	switch {
+	case batch.Status == models.BatchStatusCancelling:
+		finalStatus = models.BatchStatusCancelled
+	case !time.Now().Before(batch.ExpiresAt):
+		finalStatus = models.BatchStatusExpired
+	}
+
+	if finalStatus == models.BatchStatusExpired {
+		if err := s.expireRemaining(batch, lines); err != nil {
+			return err
+		}
+	}
+
+	if finalStatus == models.BatchStatusCompleted {
+		batch, err = s.store.Update(id, func(batch *models.Batch) {
+			batch.Status = models.BatchStatusFinalizing
+			batch.FinalizingAt = time.Now().UTC()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	outputFileID, err := s.publishResults(batch, batchOutputResults, "output")
+	if err != nil {
+		return err
+	}
+	errorFileID, err := s.publishResults(batch, batchErrorResults, "error")
+	if err != nil {
+		return err
+	}
+
+	_, err = s.store.Update(id, func(batch *models.Batch) {
+		now := time.Now().UTC()
+		batch.Status = finalStatus
+		batch.OutputFileID = outputFileID
+		batch.ErrorFileID = errorFileID
+		switch finalStatus {
+		case models.BatchStatusCompleted:
+			batch.CompletedAt = now
+		case models.BatchStatusCancelled:
+			batch.CancelledAt = now
+		case models.BatchStatusExpired:
+			batch.ExpiredAt = now
+		}
+		// The pass-through upstream key is only needed to run the batch
+		batch.APIKey = ""
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.store.RemoveResults(id, batchOutputResults, batchErrorResults)
+}
+
+// expireRemaining records an error for every line of an expired batch without a result
+func (s *BatchService) expireRemaining(batch *models.Batch, lines []models.BatchInputLine) error {
+	done, err := s.completedLines(batch.ID)
+	if err != nil {
+		return err
+	}
+
+	results, err := newBatchResults(s.store, batch.ID)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	expired := 0
+	for _, line := range lines {
+		if done[line.CustomID] {
+			continue
+		}
+		result := models.BatchOutputLine{
+			ID:       "batch_req_" + requestid.New(),
+			CustomID: line.CustomID,
+			Error: &models.BatchOutputError{
+				Code:    "batch_expired",
+				Message: "This request could not be executed before the completion window expired.",
+			},
+		}
+		if err := results.Add(result, false); err != nil {
+			return err
+		}
+		expired++
+	}
+
+	_, err = s.store.Update(batch.ID, func(batch *models.Batch) {
+		batch.RequestCounts.Failed += expired
+	})
+	return err
+}
+
+// publishResults stores a non-empty result file through the file store and returns its ID
+func (s *BatchService) publishResults(batch *models.Batch, name, kind string) (string, error) {
+	f, err := os.Open(s.store.ResultPath(batch.ID, name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open batch results: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to open batch results: %w", err)
+	}
+	if info.Size() == 0 {
+		return "", nil
+	}
+
+	file := &models.File{
+		Filename:    batch.ID + "_" + kind + ".jsonl",
+		Purpose:     models.FilePurposeBatchOutput,
+		ContentType: "application/jsonl",
+		Owner:       batch.Owner,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.files.Create(s.ctx, file, f, 0); err != nil {
+		return "", err
+	}
+	return file.ID, nil
+}
+
+// batchResults appends result lines to the output and error files of a batch
+type batchResults struct {
+	mu     sync.Mutex
+	output *os.File
+	errors *os.File
+}
+
+func newBatchResults(batchStore *store.BatchStore, id string) (*batchResults, error) {
+	output, err := os.OpenFile(batchStore.ResultPath(id, batchOutputResults), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open batch results: %w", err)
+	}
+	errorsFile, err := os.OpenFile(batchStore.ResultPath(id, batchErrorResults), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		_ = output.Close()
+		return nil, fmt.Errorf("failed to open batch results: %w", err)
+	}
+	return &batchResults{output: output, errors: errorsFile}, nil
+}
+
+// Add appends a result to the output file when it succeeded, otherwise to the error file
+func (r *batchResults) Add(result models.BatchOutputLine, succeeded bool) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := r.errors
+	if succeeded {
+		target = r.output
+	}
+	_, err = target.Write(data)
+	return err
+}
+
+func (r *batchResults) Close() {
+	_ = r.output.Close()
+	_ = r.errors.Close()
+}
+
+// formValues converts a JSON value of a batch line into form values. Arrays become
+// repeated fields.
+func formValues(raw json.RawMessage) ([]string, error) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return nil, fmt.Errorf("nested values are not supported")
+			}
+			values = append(values, fmt.Sprint(item))
+		}
+		return values, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("objects are not supported")
+	default:
+		// Numbers and booleans keep their JSON representation
+		return []string{string(bytes.TrimSpace(raw))}, nil
+	}
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{' && json.Valid(trimmed)
+}
+
+func supportedBatchEndpoints() []string {
+	endpoints := make([]string, 0, len(batchEndpoints))
+	for endpoint := range batchEndpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+)
+
+func TestBatchDispatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "json response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.WriteString(w, `{"text":"hello"}`)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"text":"hello"}`,
+		},
+		{
+			name: "implicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "hello")
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"hello"`,
+		},
+		{
+			name: "error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"error":"bad"}`, http.StatusBadRequest)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"bad"}` + "\n",
+		},
+	}
+
+	caller := requestid.Caller{VirtualKeyID: "vk-1"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &BatchService{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, ok := requestid.CallerFromContext(r.Context()); !ok || got != caller {
+					t.Errorf("caller = %+v, %v, want %+v", got, ok, caller)
+				}
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("ParseMultipartForm() error = %v", err)
+				}
+				if got := r.FormValue("file_id"); got != "file-1" {
+					t.Errorf("file_id = %q, want file-1", got)
+				}
+				w.Header().Set(requestid.Header, r.Header.Get(requestid.Header))
+				tt.handler(w, r)
+			})}
+
+			line := models.BatchInputLine{
+				CustomID: "line-1",
+				Method:   http.MethodPost,
+				URL:      "/v1/audio/transcriptions",
+				Body:     json.RawMessage(`{"model":"qwen3-asr-flash","file":"file-1"}`),
+			}
+			result := s.dispatch(context.Background(), line, caller)
+			if result.Error != nil {
+				t.Fatalf("dispatch() error = %+v", result.Error)
+			}
+			if result.CustomID != "line-1" || result.Response.RequestID != result.ID {
+				t.Errorf("dispatch() = %+v, want custom ID line-1 and request ID %s", result, result.ID)
+			}
+			if result.Response.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", result.Response.StatusCode, tt.wantStatus)
+			}
+			if got := string(result.Response.Body); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"sync"
+
+	"qwen3-compatibility/internal/audio"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+)
+
+// ErrChannelSplitUnsupported is returned when channel_mode=split is requested for audio
+// that cannot be demultiplexed: formats other than WAV and raw PCM, or mono audio
+var ErrChannelSplitUnsupported = stderrors.New("channel_mode=split requires WAV or PCM audio with at least two channels")
+
+// ErrTooManyChannels is returned when channel_mode=split is requested for audio with more
+// channels than chunking.max_channels
+var ErrTooManyChannels = stderrors.New("too many channels for channel_mode=split")
+
+// channelResult is the transcript of one channel of a recording
+type channelResult struct {
+	responses []*models.ASRResponse
+	segments  []models.TranscriptionSegment
+}
+
+// TranscribeChannels transcribes every channel of WAV or raw PCM audio separately, for
+// recordings with one speaker per channel. The channels are transcribed concurrently,
+// each cut into utterances like TranscribeSegments, with chunking.concurrency bounding
+// the utterances transcribed at the same time across all channels. It returns the combined ASR
+// response, whose text is the time-ordered transcript with every turn labeled by its
+// channel, the segments of all channels ordered by start time, and the text of each
+// channel.
+func (s *ChunkingService) TranscribeChannels(ctx context.Context, apiKey string, content io.Reader, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, []models.TranscriptionSegment, []models.ChannelTranscript, error) {
+	if !s.Supports(header) {
+		return nil, nil, nil, ErrChannelSplitUnsupported
+	}
+
+	clip, _, cleanup, err := s.openClip(content, header)
+	if stderrors.Is(err, ErrChunkingNotNeeded) {
+		return nil, nil, nil, ErrChannelSplitUnsupported
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer cleanup()
+
+	channels := clip.Format.Channels
+	if channels < 2 {
+		return nil, nil, nil, ErrChannelSplitUnsupported
+	}
+	if channels > s.config.MaxChannels {
+		return nil, nil, nil, fmt.Errorf("%w: the audio has %d channels, at most %d are supported", ErrTooManyChannels, channels, s.config.MaxChannels)
+	}
+
+	duration := clip.Duration().Seconds()
+	logging.FromContext(ctx).Info("Transcribing audio channels separately", "channels", channels, "duration", duration)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]channelResult, channels)
+	sem := s.newSemaphore()
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for ch := 0; ch < channels; ch++ {
+		wg.Add(1)
+		go func(ch int) {
+			defer wg.Done()
+
+			result, err := s.transcribeChannel(ctx, apiKey, sem, clip.Channel(ch), ch+1, header, params, validityHours)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("channel %d of %d: %w", ch+1, channels, err)
+					cancel()
+				})
+				return
+			}
+			results[ch] = result
+		}(ch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var responses []*models.ASRResponse
+	var segments []models.TranscriptionSegment
+	transcripts := make([]models.ChannelTranscript, channels)
+	for ch, result := range results {
+		responses = append(responses, result.responses...)
+		segments = append(segments, result.segments...)
+
+		var text string
+		for _, segment := range result.segments {
+			text = joinTranscripts(text, segment.Text)
+		}
+		transcripts[ch] = models.ChannelTranscript{Channel: ch + 1, Text: text}
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Start < segments[j].Start
+	})
+	for i := range segments {
+		segments[i].ID = i
+	}
+
+	merged := mergeChunkResponses(responses, duration, joinTranscripts)
+	merged.Output.Choices[0].Message.Content = []models.ASRContent{{Text: labelTranscript(segments)}}
+	return merged, segments, transcripts, nil
+}
+
+// transcribeChannel cuts one channel, counted from 1, into utterances and transcribes
+// them. The uploads are named after the channel.
+func (s *ChunkingService) transcribeChannel(ctx context.Context, apiKey string, sem chan struct{}, clip *audio.Clip, channel int, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (channelResult, error) {
+	channelHeader := *header
+	channelHeader.Filename = channelFileName(header.Filename, channel)
+
+	responses, segments, err := s.transcribeUtterances(ctx, apiKey, sem, clip, channel, &channelHeader, params, validityHours)
+	if err != nil {
+		return channelResult{}, err
+	}
+	return channelResult{responses: responses, segments: segments}, nil
+}
+
+// channelFileName names the uploads of a channel after the original file
+func channelFileName(name string, channel int) string {
+	return fmt.Sprintf("%s.ch%d.wav", baseName(name), channel)
+}
+
+// labelTranscript writes the segments as one line per turn, prefixed with the channel or
+// speaker that spoke it. Consecutive segments of the same speaker form one turn.
+func labelTranscript(segments []models.TranscriptionSegment) string {
+	var lines []string
+	var label, text string
+	flush := func() {
+		switch {
+		case text == "":
+		case label == "":
+			lines = append(lines, text)
+		default:
+			lines = append(lines, label+": "+text)
+		}
+	}
+	for _, segment := range segments {
+		if segment.Label() != label {
+			flush()
+			label, text = segment.Label(), ""
+		}
+		text = joinTranscripts(text, segment.Text)
+	}
+	flush()
+	return strings.Join(lines, "\n")
+}
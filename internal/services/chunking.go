@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"qwen3-compatibility/internal/audio"
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/metrics"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/tracing"
+)
+
+// ErrChunkingNotNeeded is returned when audio is not split: it fits in a single request,
+// or it is not in a format that can be split
+var ErrChunkingNotNeeded = stderrors.New("audio fits in a single request")
+
+// Content types of audio that can be split into chunks
+var (
+	wavContentTypes = map[string]bool{
+		"audio/wav":      true,
+		"audio/wave":     true,
+		"audio/x-wav":    true,
+		"audio/vnd.wave": true,
+	}
+	pcmContentTypes = map[string]bool{
+		"audio/pcm": true,
+	}
+)
+
+const (
+	// Resolution of the level analysis used to find pauses
+	chunkLevelWindow = 50 * time.Millisecond
+	// Number of level windows averaged when looking for a pause to cut at
+	chunkPauseWindows = 5
+	// Number of words at the end of a chunk compared with the start of the next one
+	maxOverlapTokens = 64
+	// Number of words at the chunk edges that may be misrecognized before the overlap match
+	overlapSlack = 3
+)
+
+// ChunkingService transcribes WAV and raw PCM recordings that exceed the per-request
+// limits of the ASR model. The audio is split into overlapping chunks, preferably at
+// pauses, the chunks are transcribed concurrently and the texts are stitched together.
+type ChunkingService struct {
+	uploadService IUploadService
+	asrService    IASRService
+	config        *config.ChunkingConfig
+}
+
+func NewChunkingService(uploadService IUploadService, asrService IASRService, chunkingConfig *config.ChunkingConfig) *ChunkingService {
+	return &ChunkingService{
+		uploadService: uploadService,
+		asrService:    asrService,
+		config:        chunkingConfig,
+	}
+}
+
+// chunkSpan is a range of frames [start, end) transcribed in one request
+type chunkSpan struct {
+	start, end int64
+}
+
+// Supports reports whether the file described by header is WAV or raw PCM audio
+func (s *ChunkingService) Supports(header *multipart.FileHeader) bool {
+	contentType := fileContentType(header)
+	return wavContentTypes[contentType] || pcmContentTypes[contentType]
+}
+
+// Transcribe transcribes audio in chunks and returns the stitched result as a single ASR
+// response whose usage covers the whole recording. It returns ErrChunkingNotNeeded when
+// the audio is short enough, or not in a format that can be split, so that the caller
+// can upload it as is. Only the WAV header is read in that case.
+func (s *ChunkingService) Transcribe(ctx context.Context, apiKey string, content io.Reader, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, error) {
+	if !s.Supports(header) {
+		return nil, ErrChunkingNotNeeded
+	}
+
+	clip, rawPCM, cleanup, err := s.openClip(content, header)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	maxFrames, err := s.maxChunkFrames(clip)
+	if err != nil {
+		return nil, err
+	}
+	// Raw PCM is always converted, since it carries no header the ASR model could read
+	if clip.Frames() <= maxFrames && !rawPCM {
+		return nil, ErrChunkingNotNeeded
+	}
+
+	spans, err := s.plan(clip, maxFrames)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := clip.Duration().Seconds()
+	logging.FromContext(ctx).Info("Transcribing audio in chunks", "chunks", len(spans), "duration", duration)
+	metrics.AddChunks(params.Model, len(spans))
+
+	responses, err := s.transcribeChunks(ctx, apiKey, s.newSemaphore(), clip, spans, header, params, validityHours)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeChunkResponses(responses, duration, stitchTranscripts), nil
+}
+
+// openClip validates the content against the upload limits and returns its PCM frames
+// and whether it is raw PCM. It returns ErrChunkingNotNeeded when the content cannot be
+// split.
+func (s *ChunkingService) openClip(content io.Reader, header *multipart.FileHeader) (*audio.Clip, bool, func(), error) {
+	file, cleanup, err := AsMultipartFile(content)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	if _, err := s.uploadService.ValidateFile(file, header); err != nil {
+		cleanup()
+		return nil, false, nil, err
+	}
+	if !s.Supports(header) {
+		cleanup()
+		return nil, false, nil, ErrChunkingNotNeeded
+	}
+
+	clip, rawPCM, err := openPCMClip(file, header, s.config)
+	if err != nil {
+		cleanup()
+		if stderrors.Is(err, audio.ErrNotWAV) || stderrors.Is(err, audio.ErrUnsupportedFormat) {
+			// Compressed or mislabeled audio is left to the upload validation and the ASR model
+			return nil, false, nil, ErrChunkingNotNeeded
+		}
+		return nil, false, nil, err
+	}
+
+	return clip, rawPCM, cleanup, nil
+}
+
+// openPCMClip returns the frames of WAV audio, or of raw PCM in the format set by
+// chunking.pcm_sample_rate and chunking.pcm_channels, and whether the audio is raw PCM
+func openPCMClip(file io.ReaderAt, header *multipart.FileHeader, chunkingConfig *config.ChunkingConfig) (*audio.Clip, bool, error) {
+	if !pcmContentTypes[fileContentType(header)] {
+		clip, err := audio.OpenWAV(file, header.Size)
+		return clip, false, err
+	}
+	clip, err := audio.NewClip(file, header.Size, audio.Format{
+		Encoding:      audio.EncodingPCM,
+		Channels:      chunkingConfig.PCMChannels,
+		SampleRate:    chunkingConfig.PCMSampleRate,
+		BitsPerSample: 16,
+	})
+	return clip, true, err
+}
+
+// maxChunkFrames returns the number of frames of the clip that fit in a single request
+func (s *ChunkingService) maxChunkFrames(clip *audio.Clip) (int64, error) {
+	maxFrames := min(
+		int64(s.config.MaxChunkSeconds)*int64(clip.Format.SampleRate),
+		(s.config.MaxChunkSize-audio.WAVHeaderSize)/int64(clip.Format.FrameSize()),
+	)
+	if maxFrames < 1 {
+		return 0, fmt.Errorf("chunking.max_chunk_size is too small for %d-channel audio", clip.Format.Channels)
+	}
+	return maxFrames, nil
+}
+
+// plan splits the clip into chunks of at most maxFrames frames. Each chunk ends at the
+// quietest point of the search window before its limit, and the next chunk starts the
+// overlap before that point.
+func (s *ChunkingService) plan(clip *audio.Clip, maxFrames int64) ([]chunkSpan, error) {
+	rate := int64(clip.Format.SampleRate)
+	overlap := min(int64(s.config.OverlapSeconds)*rate, maxFrames/8)
+	search := min(int64(s.config.SearchSeconds)*rate, maxFrames/4)
+
+	var spans []chunkSpan
+	total := clip.Frames()
+	start := int64(0)
+	for {
+		limit := start + maxFrames
+		if limit >= total {
+			return append(spans, chunkSpan{start: start, end: total}), nil
+		}
+
+		cut, err := quietestFrame(clip, limit-search, limit)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, chunkSpan{start: start, end: cut})
+		start = cut - overlap
+	}
+}
+
+// quietestFrame returns the middle of the quietest pause between the frames from and to
+func quietestFrame(clip *audio.Clip, from, to int64) (int64, error) {
+	levels, err := clip.Slice(from, to).Levels(chunkLevelWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to analyze audio: %w", err)
+	}
+	if len(levels) < chunkPauseWindows {
+		return to, nil
+	}
+
+	best, bestLevel := 0, 0.0
+	var sum float64
+	for i, level := range levels {
+		sum += level
+		if i >= chunkPauseWindows {
+			sum -= levels[i-chunkPauseWindows]
+		}
+		if i < chunkPauseWindows-1 {
+			continue
+		}
+		// Prefer later pauses on ties so that chunks stay as long as possible
+		if first := i - chunkPauseWindows + 1; first == 0 || sum <= bestLevel {
+			best, bestLevel = first, sum
+		}
+	}
+
+	windowFrames := clip.FrameAt(chunkLevelWindow)
+	cut := from + int64(best)*windowFrames + int64(chunkPauseWindows)*windowFrames/2
+	return min(cut, to), nil
+}
+
+// newSemaphore returns a semaphore bounding the chunks of one request transcribed at the
+// same time
+func (s *ChunkingService) newSemaphore() chan struct{} {
+	return make(chan struct{}, s.config.Concurrency)
+}
+
+// transcribeChunks uploads and transcribes the chunks concurrently, as many at a time as
+// sem allows. The first failure cancels the remaining chunks.
+func (s *ChunkingService) transcribeChunks(ctx context.Context, apiKey string, sem chan struct{}, clip *audio.Clip, spans []chunkSpan, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) ([]*models.ASRResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make([]*models.ASRResponse, len(spans))
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i, chunk := range spans {
+		wg.Add(1)
+		go func(i int, chunk chunkSpan) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			response, err := s.transcribeChunk(ctx, apiKey, clip, chunk, chunkFileName(header.Filename, i), params, validityHours)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("chunk %d of %d: %w", i+1, len(spans), err)
+					cancel()
+				})
+				return
+			}
+			responses[i] = response
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+func (s *ChunkingService) transcribeChunk(ctx context.Context, apiKey string, clip *audio.Clip, chunk chunkSpan, fileName string, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, error) {
+	ctx, span := tracing.Start(ctx, "transcription.chunk",
+		attribute.Float64("chunk.start_seconds", clip.FrameOffset(chunk.start).Seconds()),
+		attribute.Float64("chunk.end_seconds", clip.FrameOffset(chunk.end).Seconds()),
+	)
+	response, err := s.uploadAndTranscribe(ctx, apiKey, clip.Slice(chunk.start, chunk.end), fileName, params, validityHours)
+	tracing.End(span, err)
+	return response, err
+}
+
+func (s *ChunkingService) uploadAndTranscribe(ctx context.Context, apiKey string, clip *audio.Clip, fileName string, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, error) {
+	wav := clip.WAV()
+	header := &multipart.FileHeader{
+		Filename: fileName,
+		Size:     wav.Size(),
+		Header:   textproto.MIMEHeader{"Content-Type": {"audio/wav"}},
+	}
+
+	_, asrResponse, err := TranscribeFileWithFallback(ctx, s.uploadService, s.asrService, apiKey, sectionFile{wav}, header, params, UploadOptions{
+		ValidityHours: validityHours,
+		KeepSilence:   params.KeepSilence,
+	})
+	return asrResponse, err
+}
+
+// sectionFile adapts an io.SectionReader to multipart.File
+type sectionFile struct {
+	*io.SectionReader
+}
+
+func (sectionFile) Close() error {
+	return nil
+}
+
+// baseName returns the last element of name without its extension, or "audio" if that
+// leaves nothing
+func baseName(name string) string {
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if base == "" || base == "." {
+		return "audio"
+	}
+	return base
+}
+
+// chunkFileName names the upload of a chunk after the original file
+func chunkFileName(name string, index int) string {
+	return fmt.Sprintf("%s.part%03d.wav", baseName(name), index+1)
+}
+
+// mergeChunkResponses combines the chunk responses into one, concatenating the texts with
+// join. The usage reports the duration of the recording rather than the sum of the chunks.
+func mergeChunkResponses(responses []*models.ASRResponse, duration float64, join func(prev, next string) string) *models.ASRResponse {
+	merged := &models.ASRResponse{}
+	message := models.ASRMessage{Role: "assistant"}
+	var text string
+	var finishReason string
+	var requestIDs []string
+	var inputTokens, outputTokens int
+
+	for _, response := range responses {
+		if response.Request != "" {
+			requestIDs = append(requestIDs, response.Request)
+		}
+		if details := response.Usage.InputTokensDetails; details != nil {
+			inputTokens += details.TextTokens
+		}
+		outputTokens += response.Usage.OutputTokensDetails.TextTokens
+		for _, served := range response.Served {
+			if !slices.Contains(merged.Served, served) {
+				merged.Served = append(merged.Served, served)
+			}
+		}
+
+		if len(response.Output.Choices) == 0 {
+			continue
+		}
+		choice := response.Output.Choices[0]
+		finishReason = choice.FinishReason
+		message.Annotations = append(message.Annotations, choice.Message.Annotations...)
+		text = join(text, asrText(response))
+	}
+
+	message.Content = []models.ASRContent{{Text: text}}
+	merged.Output.Choices = []models.ASRChoice{{FinishReason: finishReason, Message: message}}
+	merged.Request = strings.Join(requestIDs, ",")
+	merged.Usage = models.ASRUsage{
+		InputTokensDetails:  &models.ASRInputTokensDetails{TextTokens: inputTokens},
+		OutputTokensDetails: models.ASROutputTokensDetails{TextTokens: outputTokens},
+		Seconds:             &duration,
+	}
+	return merged
+}
+
+// textToken is a word, or a single character of scripts written without spaces, at
+// text[start:end]
+type textToken struct {
+	key        string
+	start, end int
+}
+
+// stitchTranscripts appends next to prev, removing the words transcribed twice because
+// of the chunk overlap. The overlap is the longest run of words that ends near the end of
+// prev and starts near the beginning of next; words outside of it at the chunk edges are
+// often cut off mid-word and are dropped from prev in favor of next.
+func stitchTranscripts(prev, next string) string {
+	prev = strings.TrimSpace(prev)
+	next = strings.TrimSpace(next)
+	if prev == "" || next == "" {
+		return prev + next
+	}
+
+	a, b := tokenize(prev), tokenize(next)
+	bestLen, bestI, bestJ := 0, 0, 0
+	for i := max(0, len(a)-maxOverlapTokens); i < len(a); i++ {
+		for j := 0; j <= overlapSlack && j < len(b); j++ {
+			n := 0
+			for i+n < len(a) && j+n < len(b) && a[i+n].key == b[j+n].key {
+				n++
+			}
+			if n > bestLen && i+n >= len(a)-overlapSlack && overlapRunes(a[i:i+n]) >= 3 && n >= 2 {
+				bestLen, bestI, bestJ = n, i, j
+			}
+		}
+	}
+
+	if bestLen == 0 {
+		return joinTranscripts(prev, next)
+	}
+	return prev[:a[bestI+bestLen-1].end] + next[b[bestJ+bestLen-1].end:]
+}
+
+// joinTranscripts concatenates two transcripts, separated by a space unless the text
+// at the boundary is written without spaces
+func joinTranscripts(prev, next string) string {
+	prev = strings.TrimSpace(prev)
+	next = strings.TrimSpace(next)
+	if prev == "" || next == "" {
+		return prev + next
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	first, _ := utf8.DecodeRuneInString(next)
+	if isUnspacedScript(last) || isUnspacedScript(first) || unicode.IsPunct(first) {
+		return prev + next
+	}
+	return prev + " " + next
+}
+
+// asrText returns the transcript of an ASR response
+func asrText(response *models.ASRResponse) string {
+	if len(response.Output.Choices) == 0 || len(response.Output.Choices[0].Message.Content) == 0 {
+		return ""
+	}
+	return response.Output.Choices[0].Message.Content[0].Text
+}
+
+func tokenize(text string) []textToken {
+	var tokens []textToken
+	wordStart := -1
+	flush := func(end int) {
+		if wordStart >= 0 {
+			tokens = append(tokens, textToken{key: strings.ToLower(text[wordStart:end]), start: wordStart, end: end})
+			wordStart = -1
+		}
+	}
+
+	for i, r := range text {
+		switch {
+		case isUnspacedScript(r):
+			flush(i)
+			end := i + utf8.RuneLen(r)
+			tokens = append(tokens, textToken{key: text[i:end], start: i, end: end})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '\'':
+			if wordStart < 0 {
+				wordStart = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+
+	return tokens
+}
+
+func overlapRunes(tokens []textToken) int {
+	n := 0
+	for _, token := range tokens {
+		n += utf8.RuneCountInString(token.key)
+	}
+	return n
+}
+
+// isUnspacedScript reports whether r belongs to a script written without spaces between words
+func isUnspacedScript(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"qwen3-compatibility/internal/audio"
+)
+
+func TestStitchTranscripts(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+		want string
+	}{
+		{"empty prev", "", " hello ", "hello"},
+		{"empty next", "hello ", "", "hello"},
+		{"no overlap", "the quick brown fox", "jumps over the lazy dog", "the quick brown fox jumps over the lazy dog"},
+		{"exact overlap", "the quick brown fox jumps", "fox jumps over the lazy dog", "the quick brown fox jumps over the lazy dog"},
+		{"word cut off at end of prev", "see you at the meeting tomor", "the meeting tomorrow at noon", "see you at the meeting tomorrow at noon"},
+		{"word cut off at start of next", "it was a good day", "ay good day for a walk", "it was a good day for a walk"},
+		{"case insensitive", "Hello World", "hello world again", "Hello World again"},
+		{"punctuation", "well, the meeting is over.", "The meeting is over. Thanks", "well, the meeting is over. Thanks"},
+		{"unspaced script", "今天天气很好", "天气很好我们去公园", "今天天气很好我们去公园"},
+		{"single word overlap", "I said no", "no way", "I said no no way"},
+		{"short overlap", "go to a b", "a b c", "go to a b a b c"},
+		{"overlap far from end", "one two three four five six seven eight", "two three nine ten", "one two three four five six seven eight two three nine ten"},
+		{"unspaced join", "你好", "世界", "你好世界"},
+		{"punctuation join", "hello", ", world", "hello, world"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stitchTranscripts(tt.prev, tt.next); got != tt.want {
+				t.Errorf("stitchTranscripts(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+// levelSpan is a run of frames at a constant amplitude
+type levelSpan struct {
+	frames    int
+	amplitude int16
+}
+
+// pcmBytes returns 16-bit mono samples with the given spans, alternating the sign of
+// each sample
+func pcmBytes(spans ...levelSpan) []byte {
+	var buf bytes.Buffer
+	for _, span := range spans {
+		for i := 0; i < span.frames; i++ {
+			sample := span.amplitude
+			if i%2 == 1 {
+				sample = -sample
+			}
+			_ = binary.Write(&buf, binary.LittleEndian, sample)
+		}
+	}
+	return buf.Bytes()
+}
+
+// testClip returns 16 kHz mono 16-bit audio with the given spans
+func testClip(t *testing.T, spans ...levelSpan) *audio.Clip {
+	t.Helper()
+	data := pcmBytes(spans...)
+	clip, err := audio.NewClip(bytes.NewReader(data), int64(len(data)), audio.Format{
+		Encoding:      audio.EncodingPCM,
+		Channels:      1,
+		SampleRate:    16000,
+		BitsPerSample: 16,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clip
+}
+
+func TestQuietestFrame(t *testing.T) {
+	// A level window is 800 frames and a pause spans 5 windows, so the cut is 2000 frames
+	// past the start of the quietest pause
+	tests := []struct {
+		name string
+		clip *audio.Clip
+		from int64
+		to   int64
+		want int64
+	}{
+		{
+			name: "pause",
+			clip: testClip(t, levelSpan{16000, 8000}, levelSpan{8000, 0}, levelSpan{8000, 8000}),
+			to:   32000,
+			// Ties prefer the last silent window run, windows 25 to 29
+			want: 22000,
+		},
+		{
+			name: "pause after from",
+			clip: testClip(t, levelSpan{16000, 8000}, levelSpan{8000, 0}, levelSpan{8000, 8000}),
+			from: 8000,
+			to:   32000,
+			want: 22000,
+		},
+		{
+			name: "quietest of two pauses",
+			clip: testClip(t, levelSpan{8000, 8000}, levelSpan{8000, 0}, levelSpan{8000, 8000}, levelSpan{4000, 1000}, levelSpan{4000, 8000}),
+			to:   32000,
+			want: 14000,
+		},
+		{
+			name: "silence",
+			clip: testClip(t, levelSpan{32000, 0}),
+			to:   32000,
+			want: 30000,
+		},
+		{
+			name: "range shorter than a pause",
+			clip: testClip(t, levelSpan{32000, 8000}),
+			from: 1000,
+			to:   4000,
+			want: 4000,
+		},
+		{
+			name: "range past end of clip",
+			clip: testClip(t, levelSpan{8000, 8000}, levelSpan{8000, 0}),
+			to:   40000,
+			want: 14000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quietestFrame(tt.clip, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("quietestFrame() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("quietestFrame() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"call.mp3", "call"},
+		{"call.final.m4a", "call.final"},
+		{"dir/call.wav", "call"},
+		{"call", "call"},
+		{".wav", "audio"},
+		{"", "audio"},
+		{".", "audio"},
+		{"dir/", "dir"},
+	}
+
+	for _, tt := range tests {
+		if got := baseName(tt.name); got != tt.want {
+			t.Errorf("baseName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/metrics"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/tracing"
+	"qwen3-compatibility/pkg/client"
+)
+
+// ErrDiarizationDisabled is returned when diarize=true is requested but diarization is
+// not enabled
+var ErrDiarizationDisabled = stderrors.New("diarization is not enabled")
+
+// ErrDiarizationModelNotAllowed is returned when diarize=true is requested with a virtual
+// key whose allowed models do not include the diarization model
+var ErrDiarizationModelNotAllowed = stderrors.New("diarization model is not allowed for this API key")
+
+// DiarizationService transcribes uploaded audio with speaker labels through the DashScope
+// asynchronous file transcription task API. Qwen3-ASR returns plain text only, so
+// diarized requests are transcribed by a Paraformer model instead.
+type DiarizationService struct {
+	client client.TaskProvider
+	config *config.DiarizationConfig
+}
+
+func NewDiarizationService(client client.TaskProvider, diarizationConfig *config.DiarizationConfig) *DiarizationService {
+	return &DiarizationService{
+		client: client,
+		config: diarizationConfig,
+	}
+}
+
+// Model returns the model that transcribes diarized requests. Audio must be uploaded for
+// this model, since DashScope upload policies are bound to a model.
+func (s *DiarizationService) Model() string {
+	return s.config.Model
+}
+
+// Transcribe runs a file transcription task with diarization for the uploaded audio,
+// waits for it to finish and returns its transcript as an ASR response together with a
+// segment per sentence labeled with its speaker. The response text has one line per
+// speaker turn.
+func (s *DiarizationService) Transcribe(ctx context.Context, apiKey, audioURL string, params models.TranscriptionParams) (*models.ASRResponse, []models.TranscriptionSegment, error) {
+	done := metrics.UpstreamStarted(metrics.PhaseTask)
+	ctx, span := tracing.Start(ctx, "diarization.task", attribute.String("asr.model", s.config.Model))
+	transcription, task, err := s.runTask(ctx, apiKey, audioURL, params)
+	tracing.End(span, err)
+	done(err)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := diarizedSegments(transcription)
+	duration := float64(transcription.Properties.OriginalDuration) / 1000
+	if duration == 0 && task.Usage != nil {
+		duration = task.Usage.Duration
+	}
+	metrics.AddAudioSeconds(s.config.Model, duration)
+
+	response := &models.ASRResponse{
+		Output: models.ASROutput{Choices: []models.ASRChoice{{
+			FinishReason: "stop",
+			Message: models.ASRMessage{
+				Role:    "assistant",
+				Content: []models.ASRContent{{Text: labelTranscript(segments)}},
+			},
+		}}},
+		Usage:   models.ASRUsage{Seconds: &duration},
+		Request: task.RequestID,
+		Served:  []models.ServedBy{{Model: s.config.Model, Provider: config.DashScopeProvider}},
+	}
+	return response, segments, nil
+}
+
+// runTask submits the task, polls it until it finishes and fetches the transcription
+func (s *DiarizationService) runTask(ctx context.Context, apiKey, audioURL string, params models.TranscriptionParams) (*models.FileTranscription, *models.TaskResponse, error) {
+	logger := logging.FromContext(ctx)
+
+	request := &models.FileTranscriptionRequest{
+		Model: s.config.Model,
+		Input: models.FileTranscriptionInput{FileURLs: []string{audioURL}},
+		Parameters: models.FileTranscriptionParameters{
+			DiarizationEnabled: true,
+			SpeakerCount:       params.SpeakerCount,
+		},
+	}
+	if params.Language != nil {
+		request.Parameters.LanguageHints = []string{string(*params.Language)}
+	}
+
+	submitted, err := s.client.SubmitFileTranscription(ctx, apiKey, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	taskID := submitted.Output.TaskID
+	logger.Info("Diarization task submitted", "task_id", taskID)
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeout)*time.Second)
+	defer cancel()
+	task, err := s.client.WaitTask(waitCtx, apiKey, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if task.Output.TaskStatus != models.TaskStatusSucceeded {
+		return nil, nil, taskError(task.Output.TaskStatus, task.Output.Code, task.Output.Message)
+	}
+	if len(task.Output.Results) == 0 {
+		return nil, nil, errors.NewExternalServiceError("DashScope Task", fmt.Sprintf("Task %s returned no results", taskID))
+	}
+	result := task.Output.Results[0]
+	if result.SubtaskStatus != models.TaskStatusSucceeded {
+		return nil, nil, taskError(result.SubtaskStatus, result.Code, result.Message)
+	}
+
+	transcription, err := s.client.FetchTranscription(ctx, result.TranscriptionURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Debug("Diarization task finished", "task_id", taskID, "transcripts", len(transcription.Transcripts))
+	return transcription, task, nil
+}
+
+// taskError describes a task or file that did not succeed, keeping the DashScope code
+func taskError(status models.TaskStatus, code, message string) *errors.APIError {
+	apiErr := errors.NewExternalServiceError("DashScope Task", fmt.Sprintf("Task %s: %s %s", strings.ToLower(string(status)), code, message))
+	apiErr.UpstreamCode = code
+	return apiErr
+}
+
+// diarizedSegments converts the sentences of all channels into segments ordered by start
+// time. Speakers are counted from 1 in the labels.
+func diarizedSegments(transcription *models.FileTranscription) []models.TranscriptionSegment {
+	var segments []models.TranscriptionSegment
+	for _, transcript := range transcription.Transcripts {
+		for _, sentence := range transcript.Sentences {
+			text := strings.TrimSpace(sentence.Text)
+			if text == "" {
+				continue
+			}
+			segment := models.TranscriptionSegment{
+				Start: roundSeconds(time.Duration(sentence.BeginTime) * time.Millisecond),
+				End:   roundSeconds(time.Duration(sentence.EndTime) * time.Millisecond),
+				Text:  text,
+			}
+			if sentence.SpeakerID != nil {
+				segment.Speaker = fmt.Sprintf("Speaker %d", *sentence.SpeakerID+1)
+			}
+			segments = append(segments, segment)
+		}
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Start < segments[j].Start
+	})
+	for i := range segments {
+		segments[i].ID = i
+	}
+	return segments
+}
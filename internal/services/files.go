@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"time"
+
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/store"
+)
+
+var ErrFileNotFound = stderrors.New("file not found")
+
+const (
+	// Validity of OSS uploads made for stored files, in hours
+	fileUploadValidityHours = 48
+	// Cached uploads are not reused when they expire sooner than this
+	fileUploadMinRemaining = time.Hour
+)
+
+// FileService manages files uploaded through the Files API and their reuse in
+// transcription requests
+type FileService struct {
+	store         *store.FileStore
+	uploadService IUploadService
+}
+
+func NewFileService(fileStore *store.FileStore, uploadService IUploadService) *FileService {
+	return &FileService{
+		store:         fileStore,
+		uploadService: uploadService,
+	}
+}
+
+// Create stores a new file. Content larger than maxBytes is rejected with store.ErrFileTooLarge.
+func (s *FileService) Create(ctx context.Context, file *models.File, content io.Reader, maxBytes int64) error {
+	file.CreatedAt = time.Now().UTC()
+	return s.store.Create(ctx, file, content, maxBytes)
+}
+
+// Get returns the file with the given ID if it belongs to owner
+func (s *FileService) Get(id, owner string) (*models.File, error) {
+	file, err := s.store.Get(id)
+	if err != nil || file.Owner != owner {
+		return nil, ErrFileNotFound
+	}
+	return file, nil
+}
+
+// List returns the files of owner, optionally filtered by purpose, oldest first
+func (s *FileService) List(owner, purpose string) []models.File {
+	var files []models.File
+	for _, file := range s.store.List() {
+		if file.Owner == owner && (purpose == "" || file.Purpose == purpose) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Open opens the content of a file that belongs to owner
+func (s *FileService) Open(ctx context.Context, id, owner string) (io.ReadCloser, error) {
+	if _, err := s.Get(id, owner); err != nil {
+		return nil, err
+	}
+	return s.store.Open(ctx, id)
+}
+
+// Delete removes a file that belongs to owner
+func (s *FileService) Delete(ctx context.Context, id, owner string) error {
+	if _, err := s.Get(id, owner); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, id)
+}
+
+// UploadFile makes a stored file available to the ASR service. The OSS upload is cached per
+// model and upstream key, so transcribing the same file again does not upload it again.
+func (s *FileService) UploadFile(ctx context.Context, apiKey string, file *models.File, params models.TranscriptionParams) (*models.UploadResult, error) {
+	modelName := params.Model
+	uploadKey := models.FileUploadKey(modelName, logging.Fingerprint(apiKey), params.KeepSilence)
+	if upload, ok := file.Uploads[uploadKey]; ok && time.Until(upload.ExpiresAt) > fileUploadMinRemaining {
+		logging.FromContext(ctx).Debug("Reusing OSS upload of stored file", "file_id", file.ID)
+		return &models.UploadResult{
+			OSSURL:         upload.OSSURL,
+			ExpireTime:     upload.ExpiresAt,
+			ModelUsed:      modelName,
+			SilenceTrimmed: upload.SilenceTrimmed,
+		}, nil
+	}
+
+	content, err := s.store.Open(ctx, file.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = content.Close() }()
+
+	seekable, cleanup, err := AsMultipartFile(content)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	result, err := s.uploadService.UploadFile(ctx, apiKey, seekable, FileHeader(file), modelName, UploadOptions{
+		ValidityHours: fileUploadValidityHours,
+		KeepSilence:   params.KeepSilence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.store.Update(file.ID, func(file *models.File) {
+		uploads := make(map[string]models.FileUpload, len(file.Uploads)+1)
+		for key, upload := range file.Uploads {
+			if time.Now().Before(upload.ExpiresAt) {
+				uploads[key] = upload
+			}
+		}
+		uploads[uploadKey] = models.FileUpload{OSSURL: result.OSSURL, ExpiresAt: result.ExpireTime, SilenceTrimmed: result.SilenceTrimmed}
+		file.Uploads = uploads
+	})
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to cache OSS upload of stored file", "file_id", file.ID, "error", err)
+	}
+
+	return result, nil
+}
+
+// FileHeader describes a stored file as a multipart file header, for code paths shared
+// with uploaded forms
+func FileHeader(file *models.File) *multipart.FileHeader {
+	header := &multipart.FileHeader{
+		Filename: file.Filename,
+		Size:     file.Bytes,
+		Header:   textproto.MIMEHeader{},
+	}
+	if file.ContentType != "" {
+		header.Header.Set("Content-Type", file.ContentType)
+	}
+	return header
+}
+
+// AsMultipartFile returns content as a multipart.File, spooling it to a temporary file when
+// the storage backend does not support random access. The returned function releases the
+// temporary file.
+func AsMultipartFile(content io.Reader) (multipart.File, func(), error) {
+	if file, ok := content.(multipart.File); ok {
+		return file, func() {}, nil
+	}
+
+	tmp, err := os.CreateTemp("", "qwen3-file-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to buffer file: %w", err)
+	}
+	cleanup := func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+	if _, err := io.Copy(tmp, content); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to buffer file: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to buffer file: %w", err)
+	}
+	return tmp, cleanup, nil
+}
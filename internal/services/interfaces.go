@@ -2,19 +2,88 @@ package services
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
 
 	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
 )
 
 // IUploadService defines the interface for upload service
 type IUploadService interface {
-	UploadFile(ctx context.Context, apiKey string, file multipart.File, header *multipart.FileHeader, modelName string, validityHours int) (*models.UploadResult, error)
+	ValidateFile(file io.ReaderAt, header *multipart.FileHeader) (*models.AudioInfo, error)
+	PrepareFile(ctx context.Context, file multipart.File, header *multipart.FileHeader, modelName string, opts UploadOptions) (*PreparedFile, error)
+	UploadFile(ctx context.Context, apiKey string, file multipart.File, header *multipart.FileHeader, modelName string, opts UploadOptions) (*models.UploadResult, error)
 }
 
 // IASRService defines the interface for ASR service
 type IASRService interface {
-	TranscribeAudio(ctx context.Context, apiKey, audioURL, model string, language *models.SupportedLanguage, prompt string) (*models.ASRResponse, error)
+	NeedsUpload(model string) bool
+	Fallbacks(model string) []string
+	FallbackClass(err error) string
+	TranscribeInline(ctx context.Context, audio io.Reader, fileName, contentType, model string, language *models.SupportedLanguage, prompt string) (*models.ASRResponse, error)
+	TranscribeAudio(ctx context.Context, apiKey, audioURL, model string, language *models.SupportedLanguage, enableITN *bool, prompt string) (*models.ASRResponse, error)
 	ConvertToOpenAIFormat(asrResponse *models.ASRResponse, processingTimeMs int64) *models.TranscriptionResponse
 	CreateVerboseResponse(asrResponse *models.ASRResponse, processingTimeMs int64, uploadInfo *models.UploadResult) *models.VerboseTranscriptionResponse
 }
+
+// IChunkingService defines the interface for transcribing long recordings in chunks
+type IChunkingService interface {
+	Supports(header *multipart.FileHeader) bool
+	Transcribe(ctx context.Context, apiKey string, content io.Reader, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, error)
+	TranscribeSegments(ctx context.Context, apiKey string, content io.Reader, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, []models.TranscriptionSegment, error)
+	TranscribeChannels(ctx context.Context, apiKey string, content io.Reader, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, []models.TranscriptionSegment, []models.ChannelTranscript, error)
+}
+
+// IDiarizationService defines the interface for transcribing with speaker labels
+type IDiarizationService interface {
+	Model() string
+	Transcribe(ctx context.Context, apiKey, audioURL string, params models.TranscriptionParams) (*models.ASRResponse, []models.TranscriptionSegment, error)
+}
+
+// IVocabularyService defines the interface for named vocabulary lists
+type IVocabularyService interface {
+	Get(id string) (*models.Vocabulary, error)
+	List() []models.Vocabulary
+	Put(id string, terms []string) (*models.Vocabulary, error)
+	Delete(id string) error
+	Prompt(ctx context.Context, prompt string, ids []string, skipUnknown bool) (string, error)
+}
+
+// IPostProcessService defines the interface for rewriting transcripts
+type IPostProcessService interface {
+	HasDictionary(id string) bool
+	Process(ctx context.Context, params *models.TranscriptionParams, response *models.TranscriptionResponse)
+}
+
+// IJobService defines the interface for asynchronous transcription jobs
+type IJobService interface {
+	Submit(ctx context.Context, submission *JobSubmission) (*models.TranscriptionJob, error)
+	Get(id, owner string) (*models.TranscriptionJob, error)
+	Cancel(id, owner string) (*models.TranscriptionJob, error)
+	Delete(id, owner string) error
+}
+
+// IWebhookService defines the interface for managing undelivered job callbacks
+type IWebhookService interface {
+	ListDeadLetters() []models.WebhookDeadLetter
+	Redeliver(ctx context.Context, id string) (*models.WebhookDeadLetter, error)
+}
+
+// IBatchService defines the interface for OpenAI-compatible batches
+type IBatchService interface {
+	Create(ctx context.Context, req *models.CreateBatchRequest, owner string, caller requestid.Caller) (*models.Batch, error)
+	Get(id, owner string) (*models.Batch, error)
+	List(owner string) []models.Batch
+	Cancel(id, owner string) (*models.Batch, error)
+}
+
+// IFileService defines the interface for files uploaded through the Files API
+type IFileService interface {
+	Create(ctx context.Context, file *models.File, content io.Reader, maxBytes int64) error
+	Get(id, owner string) (*models.File, error)
+	List(owner, purpose string) []models.File
+	Open(ctx context.Context, id, owner string) (io.ReadCloser, error)
+	Delete(ctx context.Context, id, owner string) error
+	UploadFile(ctx context.Context, apiKey string, file *models.File, params models.TranscriptionParams) (*models.UploadResult, error)
+}
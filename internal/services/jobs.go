@@ -0,0 +1,549 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"sync"
+	"time"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+	"qwen3-compatibility/internal/store"
+)
+
+var (
+	ErrJobQueueFull  = stderrors.New("job queue is full")
+	ErrJobNotFound   = stderrors.New("job not found")
+	ErrJobFinished   = stderrors.New("job already finished")
+	ErrJobKeyRevoked = stderrors.New("API key for job is no longer valid")
+)
+
+const (
+	jobCleanupInterval = 10 * time.Minute
+	// Validity of the OSS upload made by a job, in hours
+	jobValidityHours = 48
+)
+
+// JobSubmission describes a new asynchronous transcription job
+type JobSubmission struct {
+	Params       models.TranscriptionParams
+	Audio        io.Reader
+	FileName     string
+	FileSize     int64
+	ContentType  string
+	Owner        string
+	APIKey       string
+	VirtualKeyID string
+	RequestID    string
+	CallbackURL  string
+}
+
+// JobService runs transcription jobs on a bounded worker pool, reusing the upload and ASR
+// services. Jobs are persisted so that queued and interrupted jobs resume after a restart.
+type JobService struct {
+	store         *store.JobStore
+	keyStore      *store.KeyStore
+	uploadService IUploadService
+	asrService    IASRService
+	chunking      IChunkingService
+	diarization   IDiarizationService
+	postProcess   IPostProcessService
+	webhooks      *WebhookService
+	config        *config.JobsConfig
+
+	queue  chan string
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	active map[string]context.CancelFunc
+}
+
+// NewJobService creates the job service. chunking, diarization and webhooks may be nil when
+// chunking, diarization and callbacks are disabled.
+func NewJobService(jobStore *store.JobStore, keyStore *store.KeyStore, uploadService IUploadService, asrService IASRService, chunking IChunkingService, diarization IDiarizationService, postProcess IPostProcessService, webhooks *WebhookService, jobsConfig *config.JobsConfig) *JobService {
+	ctx, stop := context.WithCancel(context.Background())
+	return &JobService{
+		store:         jobStore,
+		keyStore:      keyStore,
+		uploadService: uploadService,
+		asrService:    asrService,
+		chunking:      chunking,
+		diarization:   diarization,
+		postProcess:   postProcess,
+		webhooks:      webhooks,
+		config:        jobsConfig,
+		queue:         make(chan string, jobsConfig.QueueSize),
+		ctx:           ctx,
+		stop:          stop,
+		active:        make(map[string]context.CancelFunc),
+	}
+}
+
+// Start launches the workers, re-queues jobs left pending by a previous run and
+// starts the cleanup of expired jobs
+func (s *JobService) Start() {
+	for i := 0; i < s.config.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	var pending []string
+	for _, job := range s.store.List() {
+		if job.Status == models.JobStatusQueued || job.Status == models.JobStatusRunning {
+			pending = append(pending, job.ID)
+			continue
+		}
+		if job.APIKey != "" {
+			// Finished by a version that kept the upstream key
+			_, _ = s.store.Update(job.ID, func(job *models.TranscriptionJob) {
+				job.APIKey = ""
+			})
+		}
+		s.notify(&job)
+	}
+	if len(pending) > 0 {
+		slog.Info("Resuming pending transcription jobs", "count", len(pending))
+		s.wg.Add(1)
+		go s.requeue(pending)
+	}
+
+	s.wg.Add(1)
+	go s.cleanupLoop()
+}
+
+// Stop stops accepting work and waits for the workers to exit. Jobs interrupted by the
+// shutdown are left queued and resume on the next start.
+func (s *JobService) Stop(ctx context.Context) error {
+	s.stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Submit persists a new job and queues it for processing
+func (s *JobService) Submit(ctx context.Context, submission *JobSubmission) (*models.TranscriptionJob, error) {
+	id, err := s.store.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.TranscriptionJob{
+		ID:           id,
+		Status:       models.JobStatusQueued,
+		Params:       submission.Params,
+		FileName:     submission.FileName,
+		FileSize:     submission.FileSize,
+		ContentType:  submission.ContentType,
+		Owner:        submission.Owner,
+		VirtualKeyID: submission.VirtualKeyID,
+		RequestID:    submission.RequestID,
+		CreatedAt:    time.Now().UTC(),
+	}
+	// Virtual keys are resolved when the job runs, so only pass-through keys are persisted
+	if submission.VirtualKeyID == "" {
+		job.APIKey = submission.APIKey
+	}
+	if submission.CallbackURL != "" {
+		deliveryID, err := store.NewDeliveryID()
+		if err != nil {
+			return nil, err
+		}
+		job.Callback = &models.JobCallback{
+			URL:        submission.CallbackURL,
+			DeliveryID: deliveryID,
+			Status:     models.CallbackStatusPending,
+		}
+	}
+
+	if err := s.store.Create(job, submission.Audio); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.queue <- job.ID:
+	default:
+		_ = s.store.Delete(job.ID)
+		return nil, ErrJobQueueFull
+	}
+
+	logging.FromContext(ctx).Info("Transcription job queued", "job_id", job.ID)
+
+	result := *job
+	return &result, nil
+}
+
+// Get returns the job with the given ID if it belongs to owner
+func (s *JobService) Get(id, owner string) (*models.TranscriptionJob, error) {
+	job, err := s.store.Get(id)
+	if err != nil || job.Owner != owner {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Cancel cancels a queued or running job that belongs to owner
+func (s *JobService) Cancel(id, owner string) (*models.TranscriptionJob, error) {
+	if _, err := s.Get(id, owner); err != nil {
+		return nil, err
+	}
+
+	var alreadyFinished bool
+	job, err := s.store.Update(id, func(job *models.TranscriptionJob) {
+		if job.Status.IsTerminal() {
+			alreadyFinished = true
+			return
+		}
+		job.Status = models.JobStatusCancelled
+		job.CompletedAt = time.Now().UTC()
+		job.APIKey = ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	if alreadyFinished {
+		return job, ErrJobFinished
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.active[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	_ = s.store.RemoveAudio(id)
+	s.notify(job)
+
+	return job, nil
+}
+
+// Delete removes a finished job that belongs to owner
+func (s *JobService) Delete(id, owner string) error {
+	job, err := s.Get(id, owner)
+	if err != nil {
+		return err
+	}
+	if !job.Status.IsTerminal() {
+		return fmt.Errorf("job %s is still %s", id, job.Status)
+	}
+	return s.store.Delete(id)
+}
+
+func (s *JobService) worker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case id := <-s.queue:
+			s.process(id)
+		}
+	}
+}
+
+// requeue feeds jobs left over from a previous run into the queue
+func (s *JobService) requeue(ids []string) {
+	defer s.wg.Done()
+
+	for _, id := range ids {
+		_, _ = s.store.Update(id, func(job *models.TranscriptionJob) {
+			job.Status = models.JobStatusQueued
+		})
+
+		select {
+		case s.queue <- id:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *JobService) process(id string) {
+	job, err := s.store.Get(id)
+	if err != nil || job.Status != models.JobStatusQueued {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, time.Duration(s.config.Timeout)*time.Second)
+	defer cancel()
+
+	s.mu.Lock()
+	s.active[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, id)
+		s.mu.Unlock()
+	}()
+
+	logger := slog.Default().With("job_id", id, "request_id", job.RequestID, "model", job.Params.Model)
+	ctx = logging.WithLogger(requestid.WithID(ctx, job.RequestID), logger)
+
+	job, err = s.store.Update(id, func(job *models.TranscriptionJob) {
+		if job.Status == models.JobStatusQueued {
+			job.Status = models.JobStatusRunning
+			job.StartedAt = time.Now().UTC()
+		}
+	})
+	if err != nil || job.Status != models.JobStatusRunning {
+		return
+	}
+
+	logger.Info("Transcription job started")
+	result, runErr := s.run(ctx, job)
+	if runErr == nil {
+		s.postProcess.Process(ctx, &job.Params, result)
+	}
+	upstreamIDs := requestid.UpstreamFromContext(ctx).IDs()
+
+	var finished bool
+	job, err = s.store.Update(id, func(job *models.TranscriptionJob) {
+		job.UpstreamRequestIDs = upstreamIDs
+		switch {
+		case job.Status == models.JobStatusCancelled:
+			// Cancelled by the owner while running; Cancel sends the callback
+		case runErr == nil:
+			finished = true
+			job.Status = models.JobStatusSucceeded
+			job.Result = result
+			job.CompletedAt = time.Now().UTC()
+		case s.ctx.Err() != nil:
+			// Interrupted by shutdown; resume on the next start
+			job.Status = models.JobStatusQueued
+			job.StartedAt = time.Time{}
+		default:
+			finished = true
+			job.Status = models.JobStatusFailed
+			job.Error = jobError(runErr)
+			job.CompletedAt = time.Now().UTC()
+		}
+		if job.Status.IsTerminal() {
+			// The pass-through upstream key is only needed to run the job
+			job.APIKey = ""
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to update transcription job", "error", err)
+		return
+	}
+
+	if job.Status.IsTerminal() {
+		if err := s.store.RemoveAudio(id); err != nil {
+			logger.Warn("Failed to remove job audio", "error", err)
+		}
+	}
+
+	logger.Info("Transcription job finished", "status", job.Status, "error", runErr)
+
+	if finished {
+		s.notify(job)
+	}
+}
+
+// notify delivers the callback of a finished job when webhooks are enabled
+func (s *JobService) notify(job *models.TranscriptionJob) {
+	if s.webhooks != nil {
+		s.webhooks.Notify(job)
+	}
+}
+
+// run uploads the stored audio and transcribes it
+func (s *JobService) run(ctx context.Context, job *models.TranscriptionJob) (*models.TranscriptionResponse, error) {
+	startTime := time.Now()
+
+	apiKey, allowedModels, err := s.resolveAPIKey(job)
+	if err != nil {
+		return nil, err
+	}
+	job.Params.AllowedModels = allowedModels
+
+	file, err := os.Open(job.AudioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job audio: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	header := &multipart.FileHeader{
+		Filename: job.FileName,
+		Size:     job.FileSize,
+		Header:   textproto.MIMEHeader{"Content-Type": {job.ContentType}},
+	}
+
+	if job.Params.Diarize {
+		return s.runDiarized(ctx, startTime, apiKey, file, header, job.Params)
+	}
+
+	segmented := job.Params.ResponseFormat.HasSegments()
+	split := job.Params.ChannelMode == models.ChannelModeSplit
+	if split && s.chunking == nil {
+		return nil, ErrChannelSplitUnsupported
+	}
+
+	// Long WAV and PCM recordings are transcribed in chunks, in utterances when segment
+	// timestamps are requested, or channel by channel when the channels are split
+	if s.chunking != nil {
+		var asrResponse *models.ASRResponse
+		var segments []models.TranscriptionSegment
+		var channels []models.ChannelTranscript
+		switch {
+		case split:
+			asrResponse, segments, channels, err = s.chunking.TranscribeChannels(ctx, apiKey, file, header, job.Params, jobValidityHours)
+		case segmented:
+			asrResponse, segments, err = s.chunking.TranscribeSegments(ctx, apiKey, file, header, job.Params, jobValidityHours)
+		default:
+			asrResponse, err = s.chunking.Transcribe(ctx, apiKey, file, header, job.Params, jobValidityHours)
+		}
+		if err == nil {
+			response := s.asrService.ConvertToOpenAIFormat(asrResponse, time.Since(startTime).Milliseconds())
+			if segmented {
+				response.Segments = segments
+			}
+			response.Channels = channels
+			return response, nil
+		}
+		if !stderrors.Is(err, ErrChunkingNotNeeded) {
+			return nil, err
+		}
+	}
+
+	uploadResult, asrResponse, err := TranscribeFileWithFallback(ctx, s.uploadService, s.asrService, apiKey, file, header, job.Params, UploadOptions{
+		ValidityHours: jobValidityHours,
+		KeepSilence:   job.Params.KeepSilence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	processingTimeMs := time.Since(startTime).Milliseconds()
+	response := s.asrService.ConvertToOpenAIFormat(asrResponse, processingTimeMs)
+	response.UploadInfo = uploadResult.Info()
+	if response.Duration == 0 && uploadResult.Audio != nil {
+		// DashScope omitted usage.seconds
+		response.Duration = uploadResult.Audio.Duration
+	}
+	if segmented {
+		response.Segments = WholeSegments(response)
+	}
+
+	return response, nil
+}
+
+// runDiarized uploads the stored audio for the diarization model, keeping silence so that
+// the segments are timed against the original recording, and transcribes it with speaker
+// labels
+func (s *JobService) runDiarized(ctx context.Context, startTime time.Time, apiKey string, file multipart.File, header *multipart.FileHeader, params models.TranscriptionParams) (*models.TranscriptionResponse, error) {
+	if s.diarization == nil {
+		return nil, ErrDiarizationDisabled
+	}
+	// The key may have been restricted since the job was submitted
+	if !params.AllowsModel(s.diarization.Model()) {
+		return nil, ErrDiarizationModelNotAllowed
+	}
+
+	uploadResult, err := s.uploadService.UploadFile(ctx, apiKey, file, header, s.diarization.Model(), UploadOptions{
+		ValidityHours: jobValidityHours,
+		KeepSilence:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	asrResponse, segments, err := s.diarization.Transcribe(ctx, apiKey, uploadResult.OSSURL, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.asrService.ConvertToOpenAIFormat(asrResponse, time.Since(startTime).Milliseconds())
+	response.UploadInfo = uploadResult.Info()
+	if response.Duration == 0 && uploadResult.Audio != nil {
+		response.Duration = uploadResult.Audio.Duration
+	}
+	if params.ResponseFormat.HasSegments() {
+		response.Segments = segments
+	}
+	return response, nil
+}
+
+// resolveAPIKey returns the upstream key for a job and the models its virtual key allows,
+// looking up virtual keys at run time so that disabled or rotated-out keys stop their
+// pending jobs
+func (s *JobService) resolveAPIKey(job *models.TranscriptionJob) (string, []string, error) {
+	if job.VirtualKeyID == "" {
+		return job.APIKey, nil, nil
+	}
+	if s.keyStore == nil {
+		return "", nil, ErrJobKeyRevoked
+	}
+
+	virtualKey, err := s.keyStore.Get(job.VirtualKeyID)
+	if err != nil || virtualKey.Disabled {
+		return "", nil, ErrJobKeyRevoked
+	}
+	return virtualKey.UpstreamKey, virtualKey.AllowedModels, nil
+}
+
+func (s *JobService) cleanupLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(jobCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.cleanup()
+		}
+	}
+}
+
+// cleanup deletes finished jobs older than the retention period
+func (s *JobService) cleanup() {
+	if s.config.RetentionHours <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-time.Duration(s.config.RetentionHours) * time.Hour)
+	for _, job := range s.store.List() {
+		if job.Status.IsTerminal() && job.CompletedAt.Before(cutoff) {
+			if err := s.store.Delete(job.ID); err != nil {
+				slog.Warn("Failed to delete expired job", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+}
+
+// jobError converts a processing error into the error reported to the job owner
+func jobError(err error) *models.JobError {
+	if apiErr, ok := errors.IsAPIError(err); ok {
+		return &models.JobError{
+			Message: apiErr.Message,
+			Code:    apiErr.UpstreamCode,
+		}
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return &models.JobError{Message: "Job timed out", Code: "timeout"}
+	}
+	return &models.JobError{Message: err.Error()}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/zhconv"
+)
+
+// PostProcessService rewrites transcripts once they are converted to the OpenAI format,
+// applying the replacement dictionaries, redaction and Chinese script selected by the
+// request
+type PostProcessService struct {
+	dictionaries map[string][]replacementRule
+	redactor     *redactor
+}
+
+type replacementRule struct {
+	find    string
+	pattern *regexp.Regexp
+	replace string
+	// literal replaces matches with replace as is, without expanding $1 references
+	literal bool
+}
+
+// NewPostProcessService compiles the replacement dictionaries and profanity lists. The
+// patterns of the dictionaries are checked by config.Validate.
+func NewPostProcessService(replacementsConfig *config.ReplacementsConfig, redactionConfig *config.RedactionConfig) *PostProcessService {
+	dictionaries := make(map[string][]replacementRule, len(replacementsConfig.Dictionaries))
+	for id, rules := range replacementsConfig.Dictionaries {
+		compiled := make([]replacementRule, 0, len(rules))
+		for _, rule := range rules {
+			compiled = append(compiled, replacementRule{
+				find:    rule.Find,
+				pattern: regexp.MustCompile(rule.Pattern()),
+				replace: rule.Replace,
+				literal: !rule.Regex,
+			})
+		}
+		dictionaries[id] = compiled
+	}
+
+	return &PostProcessService{
+		dictionaries: dictionaries,
+		redactor:     newRedactor(redactionConfig),
+	}
+}
+
+// HasDictionary reports whether a replacement dictionary with the given ID is configured
+func (s *PostProcessService) HasDictionary(id string) bool {
+	_, ok := s.dictionaries[id]
+	return ok
+}
+
+// Process rewrites the text, segments and channel transcripts of the response. The rules
+// that matched the text and the redaction counts are reported in the response with
+// verbose_json. Script conversion runs last, so that dictionaries and profanity lists
+// match the script the model wrote, and redaction runs after replacements, so that
+// replaced text is masked as well. Optional fields not requested with include[] are
+// removed.
+func (s *PostProcessService) Process(ctx context.Context, params *models.TranscriptionParams, response *models.TranscriptionResponse) {
+	if !slices.Contains(params.Include, models.IncludeEmotion) {
+		response.Emotion = ""
+	}
+	if !slices.Contains(params.Include, models.IncludeAnnotations) {
+		response.Annotations = nil
+	}
+
+	// The requested language, or the detected one when none was requested
+	language := models.SupportedLanguage(response.Language)
+	if params.Language != nil {
+		language = *params.Language
+	}
+
+	hits := s.replace(ctx, params.Dictionaries, response)
+	var counts map[models.RedactionCategory]int
+	if len(params.Redact) > 0 {
+		counts = s.redactor.redact(params.Redact, language, response)
+	}
+	if params.ResponseFormat == models.ResponseFormatVerboseJSON {
+		response.Replacements = hits
+		if len(counts) > 0 {
+			response.Redactions = counts
+		}
+	}
+
+	convertScript(params.OutputScript, language, response)
+}
+
+// replace applies the rules of the dictionaries in order, each to the output of the
+// previous one
+func (s *PostProcessService) replace(ctx context.Context, dictionaryIDs []string, response *models.TranscriptionResponse) []models.ReplacementHit {
+	var hits []models.ReplacementHit
+	for _, id := range dictionaryIDs {
+		rules, ok := s.dictionaries[id]
+		if !ok {
+			logging.FromContext(ctx).Warn("Replacement dictionary not found", "dictionary_id", id)
+			continue
+		}
+
+		for i, rule := range rules {
+			if count := len(rule.pattern.FindAllStringIndex(response.Text, -1)); count > 0 {
+				hits = append(hits, models.ReplacementHit{Dictionary: id, Rule: i, Find: rule.find, Count: count})
+			}
+			response.Text = rule.apply(response.Text)
+			for j := range response.Segments {
+				response.Segments[j].Text = rule.apply(response.Segments[j].Text)
+			}
+			for j := range response.Channels {
+				response.Channels[j].Text = rule.apply(response.Channels[j].Text)
+			}
+		}
+	}
+	return hits
+}
+
+// convertScript converts Chinese transcripts to the requested script. Transcripts in
+// another language are left unchanged, since Japanese kanji share code points with
+// Chinese characters.
+func convertScript(script models.OutputScript, language models.SupportedLanguage, response *models.TranscriptionResponse) {
+	var convert func(string) string
+	switch script {
+	case models.OutputScriptHant:
+		convert = zhconv.ToTraditional
+	case models.OutputScriptHans:
+		convert = zhconv.ToSimplified
+	default:
+		return
+	}
+
+	if language != "" && !language.IsChinese() {
+		return
+	}
+
+	response.Text = convert(response.Text)
+	for i := range response.Segments {
+		response.Segments[i].Text = convert(response.Segments[i].Text)
+	}
+	for i := range response.Channels {
+		response.Channels[i].Text = convert(response.Channels[i].Text)
+	}
+}
+
+func (r replacementRule) apply(text string) string {
+	if r.literal {
+		return r.pattern.ReplaceAllLiteralString(text, r.replace)
+	}
+	return r.pattern.ReplaceAllString(text, r.replace)
+}
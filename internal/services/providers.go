@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/metrics"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/pkg/client"
+)
+
+// Provider is an ASR backend that models are routed to
+type Provider struct {
+	Name    string
+	Backend client.Backend
+	// model is the model name sent to the backend, the requested model when empty
+	model string
+}
+
+// NeedsUpload reports whether audio for the provider is uploaded to OSS and passed by URL,
+// rather than sent inline in the request
+func (p *Provider) NeedsUpload() bool {
+	return p.Backend.NeedsUpload()
+}
+
+// UpstreamModel returns the model name sent to the provider for a requested model
+func (p *Provider) UpstreamModel(model string) string {
+	if p.model != "" {
+		return p.model
+	}
+	return model
+}
+
+// ProviderRegistry chooses the ASR provider of a request by model name. Models that are
+// not routed explicitly go to DashScope. It also holds the fallback models tried when a
+// model fails with one of the configured error classes.
+type ProviderRegistry struct {
+	providers  map[string]*Provider
+	models     map[string]string
+	fallbacks  map[string][]string
+	fallbackOn map[string]bool
+}
+
+// NewProviderRegistry creates the configured providers in addition to the built-in
+// DashScope provider. Providers without a timeout use defaultTimeout seconds.
+func NewProviderRegistry(providersConfig *config.ProvidersConfig, dashscope client.Backend, defaultTimeout int) (*ProviderRegistry, error) {
+	registry := &ProviderRegistry{
+		providers: map[string]*Provider{
+			config.DashScopeProvider: {Name: config.DashScopeProvider, Backend: dashscope},
+		},
+		models:     make(map[string]string, len(providersConfig.Models)),
+		fallbacks:  make(map[string][]string, len(providersConfig.Fallbacks)),
+		fallbackOn: make(map[string]bool, len(providersConfig.FallbackOn)),
+	}
+	for model, name := range providersConfig.Models {
+		registry.models[strings.ToLower(model)] = name
+	}
+	for model, fallbacks := range providersConfig.Fallbacks {
+		registry.fallbacks[strings.ToLower(model)] = fallbacks
+	}
+	for _, class := range providersConfig.FallbackOn {
+		registry.fallbackOn[class] = true
+	}
+
+	for name, backend := range providersConfig.Backends {
+		timeout := backend.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+
+		switch backend.Type {
+		case config.ProviderTypeOpenAI:
+			registry.providers[name] = &Provider{
+				Name:    name,
+				Backend: client.NewOpenAIClient(name, backend.BaseURL, backend.APIKey, timeout),
+				model:   backend.Model,
+			}
+		default:
+			return nil, fmt.Errorf("provider %s has unknown type %q", name, backend.Type)
+		}
+	}
+
+	for model, name := range registry.models {
+		if _, ok := registry.providers[name]; !ok {
+			return nil, fmt.Errorf("model %s is routed to unknown provider %q", model, name)
+		}
+	}
+
+	return registry, nil
+}
+
+// Lookup returns the provider serving a model. Model names are matched case-insensitively,
+// since configuration keys are lowercased.
+func (r *ProviderRegistry) Lookup(model string) *Provider {
+	if name, ok := r.models[strings.ToLower(model)]; ok {
+		return r.providers[name]
+	}
+	return r.providers[config.DashScopeProvider]
+}
+
+// Fallbacks returns the models tried in order when transcription with model fails
+func (r *ProviderRegistry) Fallbacks(model string) []string {
+	return r.fallbacks[strings.ToLower(model)]
+}
+
+// FallbackClass returns the class of a transcription error when it is configured to fall
+// back to the next model, or an empty string when it is not
+func (r *ProviderRegistry) FallbackClass(err error) string {
+	if class := errorClass(err); r.fallbackOn[class] {
+		return class
+	}
+	return ""
+}
+
+// errorClass classifies an upstream error as a server error, rate limit, timeout or
+// network error. Other errors, such as rejected audio, have no class and never fall back.
+func errorClass(err error) string {
+	if apiErr, ok := errors.IsAPIError(err); ok {
+		switch {
+		case apiErr.UpstreamStatus == http.StatusTooManyRequests:
+			return config.FallbackRateLimit
+		case apiErr.UpstreamStatus >= http.StatusInternalServerError:
+			return config.FallbackServerError
+		case apiErr.UpstreamCode == config.FallbackTimeout, apiErr.UpstreamCode == config.FallbackNetwork:
+			return apiErr.UpstreamCode
+		}
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return config.FallbackTimeout
+	}
+	return ""
+}
+
+// Names returns the names of the providers, sorted
+func (r *ProviderRegistry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TranscribeWithFallback calls transcribe with params and, while it fails with an error
+// that falls back, again with each fallback model of params.Model in order. Fallback
+// models that params.AllowedModels excludes are skipped. The error of the last attempt
+// is returned when all of them fail. Nothing falls back once ctx is done.
+func TranscribeWithFallback(ctx context.Context, asrService IASRService, params models.TranscriptionParams, transcribe func(params models.TranscriptionParams) (*models.UploadResult, *models.ASRResponse, error)) (*models.UploadResult, *models.ASRResponse, error) {
+	requested := params.Model
+	var fallbacks []string
+	for _, model := range asrService.Fallbacks(requested) {
+		if params.AllowsModel(model) {
+			fallbacks = append(fallbacks, model)
+		}
+	}
+
+	for i := 0; ; i++ {
+		uploadResult, asrResponse, err := transcribe(params)
+		if err == nil {
+			return uploadResult, asrResponse, nil
+		}
+		if i == len(fallbacks) || ctx.Err() != nil {
+			return nil, nil, err
+		}
+		class := asrService.FallbackClass(err)
+		if class == "" {
+			return nil, nil, err
+		}
+
+		logging.FromContext(ctx).Warn("Transcription failed, falling back",
+			"requested_model", requested,
+			"failed_model", params.Model,
+			"fallback_model", fallbacks[i],
+			"class", class,
+			"error", err,
+		)
+		metrics.AddFallback(params.Model, fallbacks[i], class)
+		params.Model = fallbacks[i]
+	}
+}
+
+// TranscribeFileWithFallback transcribes a file like TranscribeFile, falling back to the
+// fallback models of params.Model on upstream failures
+func TranscribeFileWithFallback(ctx context.Context, uploadService IUploadService, asrService IASRService, apiKey string, file multipart.File, header *multipart.FileHeader, params models.TranscriptionParams, opts UploadOptions) (*models.UploadResult, *models.ASRResponse, error) {
+	attempt := 0
+	return TranscribeWithFallback(ctx, asrService, params, func(params models.TranscriptionParams) (*models.UploadResult, *models.ASRResponse, error) {
+		// Fallbacks read the file again from the start
+		if attempt++; attempt > 1 {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return nil, nil, fmt.Errorf("failed to rewind file: %w", err)
+			}
+		}
+		return TranscribeFile(ctx, uploadService, asrService, apiKey, file, header, params, opts)
+	})
+}
+
+// TranscribeFile transcribes a file with the provider of params.Model. For providers that
+// read from OSS, the file is uploaded and transcribed by URL; for the others it is
+// prepared like an upload and sent inline, and the upload result has no OSS URL.
+func TranscribeFile(ctx context.Context, uploadService IUploadService, asrService IASRService, apiKey string, file multipart.File, header *multipart.FileHeader, params models.TranscriptionParams, opts UploadOptions) (*models.UploadResult, *models.ASRResponse, error) {
+	if asrService.NeedsUpload(params.Model) {
+		uploadResult, err := uploadService.UploadFile(ctx, apiKey, file, header, params.Model, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		asrResponse, err := asrService.TranscribeAudio(ctx, apiKey, uploadResult.OSSURL, params.Model, params.Language, params.EnableITN, params.Prompt)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uploadResult, asrResponse, nil
+	}
+
+	prepared, err := uploadService.PrepareFile(ctx, file, header, params.Model, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = prepared.Close() }()
+
+	asrResponse, err := asrService.TranscribeInline(ctx, prepared, prepared.Name, prepared.ContentType, params.Model, params.Language, params.Prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &models.UploadResult{
+		ModelUsed:      params.Model,
+		SilenceTrimmed: prepared.SilenceTrimmed,
+		Audio:          prepared.Audio,
+	}, asrResponse, nil
+}
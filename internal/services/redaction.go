@@ -0,0 +1,258 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/models"
+)
+
+// Detectors of personal data. Numbers are matched in written form, as transcribed with
+// inverse text normalization; separators between digits may be spaces or hyphens.
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	// Resident identity card numbers of mainland China
+	idNumberPattern = regexp.MustCompile(`\b\d{17}[\dXx]\b`)
+	cardPattern     = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	// Mainland mobile and landline numbers, and other numbers with a country code
+	phonePattern = regexp.MustCompile(`(?:\+86[ -]?|\b86[ -]?|\b)1[3-9]\d(?:[ -]?\d){8}\b|\b0\d{2,3}[ -]?\d{7,8}\b|\+\d{1,3}(?:[ -]?\d){7,12}\b`)
+	// Phone numbers with a country code can have as many digits as a card and pass the
+	// Luhn check by chance, so they are not taken for cards
+	phoneNumberPattern = regexp.MustCompile(`^(?:` + phonePattern.String() + `)$`)
+)
+
+// defaultProfanity lists the words masked in languages without a list in the
+// configuration. Chinese and Cantonese words are in Simplified characters, as the model
+// writes them.
+var defaultProfanity = map[models.SupportedLanguage][]string{
+	models.LanguageEn: {
+		"fuck", "fucking", "fucked", "motherfucker", "shit", "bullshit", "bitch", "asshole",
+		"bastard", "dick", "cunt",
+	},
+	models.LanguageZh: {
+		"操你妈", "他妈的", "妈的", "傻逼", "煞笔", "王八蛋", "混蛋", "贱人", "婊子", "卧槽",
+		"草泥马", "狗日的",
+	},
+	models.LanguageYue: {
+		"屌你老母", "仆街", "扑街", "冚家铲", "顶你个肺",
+	},
+}
+
+// redactor masks personal data and profanity in transcripts
+type redactor struct {
+	maskStyle string
+	maskChar  rune
+	// profanity matches the words of each language, nil for languages without words
+	profanity map[models.SupportedLanguage]*regexp.Regexp
+}
+
+func newRedactor(redactionConfig *config.RedactionConfig) *redactor {
+	maskChar, _ := utf8.DecodeRuneInString(redactionConfig.MaskChar)
+	r := &redactor{
+		maskStyle: redactionConfig.MaskStyle,
+		maskChar:  maskChar,
+		profanity: make(map[models.SupportedLanguage]*regexp.Regexp),
+	}
+
+	lists := make(map[models.SupportedLanguage][]string, len(defaultProfanity))
+	for language, words := range defaultProfanity {
+		lists[language] = words
+	}
+	for language, words := range redactionConfig.Profanity {
+		lists[models.SupportedLanguage(language)] = words
+	}
+	for language, words := range lists {
+		if pattern := profanityPattern(words); pattern != nil {
+			r.profanity[language] = pattern
+		}
+	}
+
+	return r
+}
+
+// profanityPattern matches any of the words, case-insensitively and longest first. Words
+// starting or ending with a letter or digit of a Latin script only match whole words;
+// scripts written without spaces, such as Chinese, match anywhere.
+func profanityPattern(words []string) *regexp.Regexp {
+	words = slices.Clone(words)
+	slices.SortFunc(words, func(a, b string) int {
+		return utf8.RuneCountInString(b) - utf8.RuneCountInString(a)
+	})
+
+	alternatives := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		alternative := regexp.QuoteMeta(word)
+		if first, _ := utf8.DecodeRuneInString(word); isASCIIWordRune(first) {
+			alternative = `\b` + alternative
+		}
+		if last, _ := utf8.DecodeLastRuneInString(word); isASCIIWordRune(last) {
+			alternative += `\b`
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(alternatives, "|") + `)`)
+}
+
+func isASCIIWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// redact masks the categories in the text, segments and channel transcripts of the
+// response and returns the number of matches masked in the text by category. Profanity
+// is masked with the list of the language, or with every list when it is unknown.
+func (r *redactor) redact(categories []models.RedactionCategory, language models.SupportedLanguage, response *models.TranscriptionResponse) map[models.RedactionCategory]int {
+	counts := make(map[models.RedactionCategory]int)
+	for _, category := range models.RedactionCategories {
+		if !slices.Contains(categories, category) {
+			continue
+		}
+
+		for _, pattern := range r.patterns(category, language) {
+			var count int
+			response.Text, count = r.mask(category, pattern, response.Text)
+			counts[category] += count
+			for i := range response.Segments {
+				response.Segments[i].Text, _ = r.mask(category, pattern, response.Segments[i].Text)
+			}
+			for i := range response.Channels {
+				response.Channels[i].Text, _ = r.mask(category, pattern, response.Channels[i].Text)
+			}
+		}
+		if counts[category] == 0 {
+			delete(counts, category)
+		}
+	}
+	return counts
+}
+
+func (r *redactor) patterns(category models.RedactionCategory, language models.SupportedLanguage) []*regexp.Regexp {
+	switch category {
+	case models.RedactionEmail:
+		return []*regexp.Regexp{emailPattern}
+	case models.RedactionIDNumber:
+		return []*regexp.Regexp{idNumberPattern}
+	case models.RedactionCreditCard:
+		return []*regexp.Regexp{cardPattern}
+	case models.RedactionPhone:
+		return []*regexp.Regexp{phonePattern}
+	case models.RedactionProfanity:
+		if language != "" && models.IsValidLanguage(string(language)) {
+			if pattern := r.profanity[language]; pattern != nil {
+				return []*regexp.Regexp{pattern}
+			}
+			return nil
+		}
+		patterns := make([]*regexp.Regexp, 0, len(r.profanity))
+		for _, pattern := range r.profanity {
+			patterns = append(patterns, pattern)
+		}
+		return patterns
+	}
+	return nil
+}
+
+// mask replaces the matches of the pattern that pass the checks of the category and
+// returns the text with the number of masked matches
+func (r *redactor) mask(category models.RedactionCategory, pattern *regexp.Regexp, text string) (string, int) {
+	count := 0
+	masked := pattern.ReplaceAllStringFunc(text, func(match string) string {
+		switch category {
+		case models.RedactionIDNumber:
+			if !validIDNumber(match) {
+				return match
+			}
+		case models.RedactionCreditCard:
+			if !luhnValid(match) || phoneNumberPattern.MatchString(match) {
+				return match
+			}
+		}
+		count++
+		return r.maskMatch(category, match)
+	})
+	return masked, count
+}
+
+// maskMatch masks a match in the configured style. The asterisk style replaces letters and
+// digits and keeps separators; the partial style also keeps the last four digits of
+// numbers, the first character of words and the domain of email addresses.
+func (r *redactor) maskMatch(category models.RedactionCategory, match string) string {
+	switch r.maskStyle {
+	case config.MaskStyleLabel:
+		return "[" + strings.ToUpper(string(category)) + "]"
+	case config.MaskStylePartial:
+		switch category {
+		case models.RedactionEmail:
+			at := strings.IndexByte(match, '@')
+			first, size := utf8.DecodeRuneInString(match)
+			return string(first) + r.maskRunes(match[size:at], 0) + match[at:]
+		case models.RedactionProfanity:
+			first, size := utf8.DecodeRuneInString(match)
+			return string(first) + r.maskRunes(match[size:], 0)
+		default:
+			return r.maskRunes(match, 4)
+		}
+	default:
+		return r.maskRunes(match, 0)
+	}
+}
+
+// maskRunes replaces letters and digits with the mask character, except for the last
+// keep ones
+func (r *redactor) maskRunes(text string, keep int) string {
+	runes := []rune(text)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		runes[i] = r.maskChar
+	}
+	return string(runes)
+}
+
+// validIDNumber checks the ISO 7064 MOD 11-2 check character of a resident identity card
+// number
+func validIDNumber(number string) bool {
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i, weight := range weights {
+		sum += int(number[i]-'0') * weight
+	}
+	return strings.ToUpper(number[17:]) == string("10X98765432"[sum%11])
+}
+
+// luhnValid checks the Luhn check digit of a card number, ignoring separators
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			continue
+		}
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"maps"
+	"testing"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/models"
+)
+
+func TestValidIDNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"11010519491231002X", true},
+		{"11010519491231002x", true},
+		{"440106199003000003", true},
+		{"440106199003000206", true},
+		{"110105194912310020", false},
+		{"440106199003000004", false},
+		{"44010619900300000X", false},
+	}
+
+	for _, tt := range tests {
+		if got := validIDNumber(tt.number); got != tt.want {
+			t.Errorf("validIDNumber(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"4111111111111111", true},
+		{"4111 1111 1111 1111", true},
+		{"4111-1111-1111-1111", true},
+		{"4222222222222", true},
+		{"6222021000112233449", true},
+		{"4111111111111112", false},
+		{"4111 1111 1111 1112", false},
+		{"6222021000112233440", false},
+	}
+
+	for _, tt := range tests {
+		if got := luhnValid(tt.number); got != tt.want {
+			t.Errorf("luhnValid(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	all := []models.RedactionCategory{models.RedactionEmail, models.RedactionIDNumber, models.RedactionCreditCard, models.RedactionPhone}
+	card := []models.RedactionCategory{models.RedactionCreditCard}
+
+	tests := []struct {
+		name       string
+		categories []models.RedactionCategory
+		text       string
+		want       string
+		wantCounts map[models.RedactionCategory]int
+	}{
+		{
+			name:       "mobile",
+			categories: all,
+			text:       "call 13812345678 now",
+			want:       "call [PHONE] now",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionPhone: 1},
+		},
+		{
+			name:       "spaced mobile with country code",
+			categories: all,
+			text:       "call +86 138 1234 5678",
+			want:       "call [PHONE]",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionPhone: 1},
+		},
+		{
+			name:       "hyphenated landline",
+			categories: all,
+			text:       "office 010-12345678",
+			want:       "office [PHONE]",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionPhone: 1},
+		},
+		{
+			name:       "spaced card",
+			categories: all,
+			text:       "card 4111 1111 1111 1111 thanks",
+			want:       "card [CREDIT_CARD] thanks",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionCreditCard: 1},
+		},
+		{
+			name:       "hyphenated card",
+			categories: all,
+			text:       "card 4111-1111-1111-1111",
+			want:       "card [CREDIT_CARD]",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionCreditCard: 1},
+		},
+		{
+			name:       "card failing the Luhn check",
+			categories: all,
+			text:       "order 4111 1111 1111 1112",
+			want:       "order 4111 1111 1111 1112",
+			wantCounts: map[models.RedactionCategory]int{},
+		},
+		{
+			name:       "id number with X",
+			categories: all,
+			text:       "id 11010519491231002X.",
+			want:       "id [ID_NUMBER].",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionIDNumber: 1},
+		},
+		{
+			name:       "id number failing the checksum",
+			categories: []models.RedactionCategory{models.RedactionIDNumber},
+			text:       "id 110105194912310020",
+			want:       "id 110105194912310020",
+			wantCounts: map[models.RedactionCategory]int{},
+		},
+		{
+			// Valid under both checks; id numbers are masked first
+			name:       "id number passing the Luhn check",
+			categories: all,
+			text:       "id 440106199003000206",
+			want:       "id [ID_NUMBER]",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionIDNumber: 1},
+		},
+		{
+			name:       "id number passing the Luhn check without id redaction",
+			categories: card,
+			text:       "id 440106199003000206",
+			want:       "id [CREDIT_CARD]",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionCreditCard: 1},
+		},
+		{
+			name:       "id number failing the Luhn check without id redaction",
+			categories: card,
+			text:       "id 440106199003000003",
+			want:       "id 440106199003000003",
+			wantCounts: map[models.RedactionCategory]int{},
+		},
+		{
+			name:       "phone is too short for a card",
+			categories: card,
+			text:       "call 13812345678",
+			want:       "call 13812345678",
+			wantCounts: map[models.RedactionCategory]int{},
+		},
+		{
+			name:       "email",
+			categories: all,
+			text:       "mail li.wei@example.com.cn today",
+			want:       "mail [EMAIL] today",
+			wantCounts: map[models.RedactionCategory]int{models.RedactionEmail: 1},
+		},
+	}
+
+	r := newRedactor(&config.RedactionConfig{MaskStyle: config.MaskStyleLabel, MaskChar: "*"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &models.TranscriptionResponse{
+				Text:     tt.text,
+				Segments: []models.TranscriptionSegment{{Text: tt.text}},
+			}
+			counts := r.redact(tt.categories, "", response)
+			if response.Text != tt.want {
+				t.Errorf("text = %q, want %q", response.Text, tt.want)
+			}
+			if response.Segments[0].Text != tt.want {
+				t.Errorf("segment text = %q, want %q", response.Segments[0].Text, tt.want)
+			}
+			if !maps.Equal(counts, tt.wantCounts) {
+				t.Errorf("counts = %v, want %v", counts, tt.wantCounts)
+			}
+		})
+	}
+}
+
+func TestMaskStyles(t *testing.T) {
+	tests := []struct {
+		style string
+		text  string
+		want  string
+	}{
+		{config.MaskStyleAsterisk, "card 4111-1111-1111-1111", "card ****-****-****-****"},
+		{config.MaskStylePartial, "card 4111 1111 1111 1111", "card **** **** **** 1111"},
+		{config.MaskStylePartial, "id 11010519491231002X", "id **************002X"},
+		{config.MaskStylePartial, "mail li.wei@example.com", "mail l*.***@example.com"},
+		{config.MaskStyleLabel, "call 13812345678", "call [PHONE]"},
+	}
+
+	categories := []models.RedactionCategory{models.RedactionEmail, models.RedactionIDNumber, models.RedactionCreditCard, models.RedactionPhone}
+	for _, tt := range tests {
+		r := newRedactor(&config.RedactionConfig{MaskStyle: tt.style, MaskChar: "*"})
+		response := &models.TranscriptionResponse{Text: tt.text}
+		r.redact(categories, "", response)
+		if response.Text != tt.want {
+			t.Errorf("%s: text = %q, want %q", tt.style, response.Text, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"io"
+	"math"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"qwen3-compatibility/internal/audio"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/metrics"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/vad"
+)
+
+// TranscribeSegments cuts WAV or raw PCM audio into utterances separated by pauses,
+// transcribes each utterance and returns the combined ASR response together with one
+// segment per utterance, timed by its offsets in the recording. It returns
+// ErrChunkingNotNeeded when the audio is not in a format that can be split.
+func (s *ChunkingService) TranscribeSegments(ctx context.Context, apiKey string, content io.Reader, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) (*models.ASRResponse, []models.TranscriptionSegment, error) {
+	if !s.Supports(header) {
+		return nil, nil, ErrChunkingNotNeeded
+	}
+
+	clip, _, cleanup, err := s.openClip(content, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cleanup()
+
+	duration := clip.Duration().Seconds()
+	responses, segments, err := s.transcribeUtterances(ctx, apiKey, s.newSemaphore(), clip, 0, header, params, validityHours)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mergeChunkResponses(responses, duration, joinTranscripts), segments, nil
+}
+
+// transcribeUtterances cuts the clip into utterances and transcribes them, returning the
+// ASR responses and a segment for every utterance with speech. A channel other than 0
+// is recorded in the segments. sem bounds the utterances transcribed at the same time.
+func (s *ChunkingService) transcribeUtterances(ctx context.Context, apiKey string, sem chan struct{}, clip *audio.Clip, channel int, header *multipart.FileHeader, params models.TranscriptionParams, validityHours int) ([]*models.ASRResponse, []models.TranscriptionSegment, error) {
+	spans, err := s.utterances(clip)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logging.FromContext(ctx).Info("Transcribing audio in segments", "segments", len(spans), "duration", clip.Duration().Seconds(), "channel", channel)
+	metrics.AddChunks(params.Model, len(spans))
+
+	responses, err := s.transcribeChunks(ctx, apiKey, sem, clip, spans, header, params, validityHours)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := make([]models.TranscriptionSegment, 0, len(spans))
+	for i, response := range responses {
+		text := strings.TrimSpace(asrText(response))
+		if text == "" {
+			continue
+		}
+		segments = append(segments, models.TranscriptionSegment{
+			ID:      len(segments),
+			Start:   roundSeconds(clip.FrameOffset(spans[i].start)),
+			End:     roundSeconds(clip.FrameOffset(spans[i].end)),
+			Text:    text,
+			Channel: channel,
+		})
+	}
+
+	return responses, segments, nil
+}
+
+// utterances finds the spans of speech in the clip. Pauses shorter than
+// chunking.min_silence_ms do not end an utterance, and utterances longer than
+// chunking.max_segment_seconds are cut at their quietest point.
+func (s *ChunkingService) utterances(clip *audio.Clip) ([]chunkSpan, error) {
+	opts := vad.DefaultOptions()
+	opts.MinSilence = time.Duration(s.config.MinSilenceMs) * time.Millisecond
+	speech, err := vad.Detect(clip, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFrames, err := s.maxChunkFrames(clip)
+	if err != nil {
+		return nil, err
+	}
+	maxFrames = min(maxFrames, int64(s.config.MaxSegmentSeconds)*int64(clip.Format.SampleRate))
+
+	var spans []chunkSpan
+	for _, utterance := range speech {
+		start := utterance.Start
+		for utterance.End-start > maxFrames {
+			cut, err := quietestFrame(clip, start+maxFrames/2, start+maxFrames)
+			if err != nil {
+				return nil, err
+			}
+			spans = append(spans, chunkSpan{start: start, end: cut})
+			start = cut
+		}
+		spans = append(spans, chunkSpan{start: start, end: utterance.End})
+	}
+
+	return spans, nil
+}
+
+// WholeSegments returns a single segment spanning the response, for audio that was
+// transcribed without being cut into utterances
+func WholeSegments(response *models.TranscriptionResponse) []models.TranscriptionSegment {
+	if response.Text == "" {
+		return nil
+	}
+	return []models.TranscriptionSegment{{
+		ID:   0,
+		End:  response.Duration,
+		Text: response.Text,
+	}}
+}
+
+func roundSeconds(d time.Duration) float64 {
+	return math.Round(d.Seconds()*1000) / 1000
+}
@@ -2,93 +2,336 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"mime"
 	"mime/multipart"
+	"net/textproto"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"qwen3-compatibility/internal/config"
 	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/media"
+	"qwen3-compatibility/internal/metrics"
 	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/tracing"
+	"qwen3-compatibility/internal/transcode"
+	"qwen3-compatibility/internal/vad"
 	"qwen3-compatibility/pkg/client"
 )
 
+// Less silence than this is not worth rewriting the file for
+const minSilenceTrimmed = 500 * time.Millisecond
+
 type UploadService struct {
-	client client.Uploader
-	config *config.UploadConfig
+	client    client.Uploader
+	config    *config.UploadConfig
+	vadConfig *config.VADConfig
+	// chunkingConfig describes the format of raw PCM uploads
+	chunkingConfig *config.ChunkingConfig
+	// transcoder is nil when transcoding is disabled or ffmpeg is missing
+	transcoder *transcode.Transcoder
 }
 
-func NewUploadService(client client.Uploader, uploadConfig *config.UploadConfig) *UploadService {
+func NewUploadService(client client.Uploader, uploadConfig *config.UploadConfig, vadConfig *config.VADConfig, chunkingConfig *config.ChunkingConfig, transcoder *transcode.Transcoder) *UploadService {
 	return &UploadService{
-		client: client,
-		config: uploadConfig,
+		client:         client,
+		config:         uploadConfig,
+		vadConfig:      vadConfig,
+		chunkingConfig: chunkingConfig,
+		transcoder:     transcoder,
 	}
 }
 
-// ValidateFile validates uploaded file
-func (s *UploadService) ValidateFile(header *multipart.FileHeader) error {
+// UploadOptions control how a file is uploaded
+type UploadOptions struct {
+	// ValidityHours is the time the uploaded file is expected to stay available
+	ValidityHours int
+	// KeepSilence uploads WAV and PCM audio as is instead of trimming silence
+	KeepSilence bool
+}
+
+// ValidateFile validates an uploaded file and returns its audio stream as stated by its
+// headers, or nil when the format cannot be probed. The content type is sniffed from the
+// first bytes of the file and replaces the Content-Type of header, so that later steps
+// rely on what the file is rather than what the client labeled it.
+func (s *UploadService) ValidateFile(file io.ReaderAt, header *multipart.FileHeader) (*models.AudioInfo, error) {
 	// Check file size
 	if header.Size > s.config.MaxFileSize {
-		return errors.NewFileSizeError(s.config.MaxFileSize)
+		return nil, errors.NewFileSizeError(s.config.MaxFileSize)
 	}
 
 	// Detect content type
-	contentType := header.Header.Get("Content-Type")
-	if contentType == "" {
-		// Fallback to detection by file extension
-		ext := strings.ToLower(filepath.Ext(header.Filename))
-		contentType = mime.TypeByExtension(ext)
+	contentType, err := s.detectContentType(io.NewSectionReader(file, 0, header.Size), header)
+	if err != nil {
+		return nil, err
 	}
-
 	if contentType == "" {
-		return errors.NewFileTypeError(s.config.AllowedTypes)
+		return nil, errors.NewFileTypeError(s.config.AllowedTypes)
 	}
 
 	// Check if content type is allowed
 	if !s.isContentTypeAllowed(contentType) {
-		return errors.NewFileTypeError(s.config.AllowedTypes)
+		return nil, errors.NewFileTypeError(s.config.AllowedTypes)
+	}
+
+	if header.Header == nil {
+		header.Header = textproto.MIMEHeader{}
+	}
+	header.Header.Set("Content-Type", contentType)
+
+	// Check duration; files whose headers cannot be read are left to the ASR model
+	info := probeFile(file, header.Size, contentType)
+	if limit := s.config.MaxDurationSeconds; limit > 0 && info != nil && info.Duration > float64(limit) {
+		return nil, errors.NewDurationError(limit)
+	}
+
+	return info, nil
+}
+
+// probeFile reads the audio stream of a file from its headers, or returns nil when the
+// format is not supported or the headers are unreadable
+func probeFile(file io.ReaderAt, size int64, contentType string) *models.AudioInfo {
+	info, err := media.Probe(file, size, contentType)
+	if err != nil {
+		return nil
+	}
+	return &models.AudioInfo{
+		ContentType: contentType,
+		Duration:    roundSeconds(info.Duration),
+		SampleRate:  info.SampleRate,
+		Channels:    info.Channels,
+		Bitrate:     info.Bitrate,
+	}
+}
+
+// detectContentType returns the content type of a file from its content. The declared type
+// is kept when it names the same container, which may be more specific, for example
+// audio/webm rather than video/webm. Headerless PCM is taken as declared.
+func (s *UploadService) detectContentType(content io.Reader, header *multipart.FileHeader) (string, error) {
+	declared := fileContentType(header)
+	sniffed, err := media.Sniff(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	switch {
+	case sniffed == "" && media.IsRaw(declared):
+		return declared, nil
+	case sniffed == "":
+		return "", nil
+	case media.IsGeneric(declared):
+		return sniffed, nil
+	case declared == sniffed || media.SameFamily(declared, sniffed):
+		return declared, nil
+	default:
+		return "", errors.NewFileTypeMismatchError(declared, sniffed)
 	}
+}
+
+// PreparedFile is a validated file ready to be sent upstream, with silence trimmed and
+// transcoded when enabled
+type PreparedFile struct {
+	multipart.File
+	Name        string
+	Size        int64
+	ContentType string
+	// SilenceTrimmed is the seconds of silence removed
+	SilenceTrimmed float64
+	// Audio is the original file as probed, nil when it could not be probed
+	Audio *models.AudioInfo
 
+	cleanup func()
+}
+
+// Close removes the temporary files of the prepared file. The original file is left open.
+func (f *PreparedFile) Close() error {
+	if f.cleanup != nil {
+		f.cleanup()
+	}
 	return nil
 }
 
-// UploadFile uploads a file and returns the upload result
-func (s *UploadService) UploadFile(ctx context.Context, apiKey string, file multipart.File, header *multipart.FileHeader, modelName string, validityHours int) (*models.UploadResult, error) {
+// PrepareFile validates a file and applies the conversions done before it is sent
+// upstream: silence trimming of WAV and PCM audio and transcoding. The caller closes the result.
+func (s *UploadService) PrepareFile(ctx context.Context, file multipart.File, header *multipart.FileHeader, modelName string, opts UploadOptions) (*PreparedFile, error) {
 	// Validate file first
-	if err := s.ValidateFile(header); err != nil {
+	audioInfo, err := s.ValidateFile(file, header)
+	if err != nil {
 		return nil, err
 	}
 
-	// Get upload policy
-	policy, err := s.client.GetUploadPolicy(ctx, apiKey, modelName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get upload policy: %w", err)
+	prepared := &PreparedFile{
+		File:        file,
+		Name:        header.Filename,
+		Size:        header.Size,
+		ContentType: fileContentType(header),
+		Audio:       audioInfo,
+	}
+
+	// Remove silence from WAV and PCM audio. The result is WAV, so raw PCM becomes a
+	// WAV file.
+	if s.vadConfig.Enabled && !opts.KeepSilence && (wavContentTypes[prepared.ContentType] || pcmContentTypes[prepared.ContentType]) {
+		if compacted, saved, ok := s.trimSilence(ctx, file, header); ok {
+			prepared.File, prepared.Size, prepared.SilenceTrimmed = sectionFile{compacted}, compacted.Size(), saved
+			if pcmContentTypes[prepared.ContentType] {
+				prepared.Name, prepared.ContentType = baseName(prepared.Name)+".wav", "audio/wav"
+			}
+			metrics.AddSilenceTrimmed(modelName, saved)
+		}
 	}
 
 	// Generate file name if empty
-	fileName := header.Filename
-	if fileName == "" {
-		fileName = fmt.Sprintf("upload_%d", time.Now().Unix())
+	if prepared.Name == "" {
+		prepared.Name = fmt.Sprintf("upload_%d", time.Now().Unix())
+	}
+
+	// Convert to mono speech audio, dropping any video
+	if s.transcoder != nil && !s.isTranscoded(audioInfo) {
+		if output, ok := s.transcode(ctx, prepared.File, prepared.Size); ok {
+			prepared.File, prepared.Size = output, output.Size
+			prepared.Name, prepared.ContentType = output.FileName(prepared.Name), output.ContentType
+			prepared.cleanup = func() { _ = output.Close() }
+		}
+	}
+
+	return prepared, nil
+}
+
+// UploadFile uploads a file and returns the upload result
+func (s *UploadService) UploadFile(ctx context.Context, apiKey string, file multipart.File, header *multipart.FileHeader, modelName string, opts UploadOptions) (*models.UploadResult, error) {
+	prepared, err := s.PrepareFile(ctx, file, header, modelName, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = prepared.Close() }()
+	size := prepared.Size
+
+	// Get upload policy
+	done := metrics.UpstreamStarted(metrics.PhasePolicy)
+	policyCtx, span := tracing.Start(ctx, "upload.get_policy", attribute.String("asr.model", modelName))
+	policy, err := s.client.GetUploadPolicy(policyCtx, apiKey, modelName)
+	tracing.End(span, err)
+	done(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload policy: %w", err)
 	}
 
 	// Upload file to OSS
-	ossURL, err := s.client.UploadToOSS(ctx, policy, file, fileName)
+	done = metrics.UpstreamStarted(metrics.PhaseOSSUpload)
+	uploadCtx, span := tracing.Start(ctx, "upload.oss_upload", attribute.Int64("upload.size_bytes", size))
+	ossURL, err := s.client.UploadToOSS(uploadCtx, policy, prepared, prepared.Name)
+	tracing.End(span, err)
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload file to OSS: %w", err)
 	}
+	metrics.AddUploadBytes(modelName, size)
 
 	// Calculate expire time
-	expireTime := time.Now().Add(time.Duration(validityHours) * time.Hour)
+	expireTime := time.Now().Add(time.Duration(opts.ValidityHours) * time.Hour)
 
 	return &models.UploadResult{
-		OSSURL:     ossURL,
-		ExpireTime: expireTime,
-		ModelUsed:  modelName,
+		OSSURL:         ossURL,
+		ExpireTime:     expireTime,
+		ModelUsed:      modelName,
+		SilenceTrimmed: prepared.SilenceTrimmed,
+		Audio:          prepared.Audio,
 	}, nil
 }
 
+// isTranscoded reports whether the probed audio is already in the codec, sample rate and
+// channel count the transcoder produces
+func (s *UploadService) isTranscoded(info *models.AudioInfo) bool {
+	return info != nil &&
+		media.SameFamily(info.ContentType, s.transcoder.ContentType()) &&
+		info.SampleRate == s.transcoder.SampleRate() &&
+		info.Channels == 1
+}
+
+// transcode converts the file with ffmpeg. It returns false when ffmpeg fails or times
+// out; the file is then uploaded as is.
+func (s *UploadService) transcode(ctx context.Context, file multipart.File, size int64) (*transcode.Output, bool) {
+	transcodeCtx, span := tracing.Start(ctx, "upload.transcode", attribute.Int64("upload.size_bytes", size))
+	output, err := s.transcoder.Transcode(transcodeCtx, file, size)
+	tracing.End(span, err)
+	if err != nil {
+		result := "failed"
+		if stderrors.Is(err, context.DeadlineExceeded) {
+			result = "timeout"
+		}
+		metrics.AddTranscode(result)
+		logging.FromContext(ctx).Warn("Transcoding failed, uploading original file", "error", err)
+		return nil, false
+	}
+
+	metrics.AddTranscode("ok")
+	logging.FromContext(ctx).Debug("Transcoded file before upload", "size", size, "transcoded_size", output.Size)
+	return output, true
+}
+
+// trimSilence returns the WAV file without its leading and trailing silence and with long
+// pauses shortened, and the seconds removed. It returns false when the file cannot be
+// parsed, contains no speech or has no silence worth removing; the file is then uploaded
+// as is.
+func (s *UploadService) trimSilence(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*io.SectionReader, float64, bool) {
+	logger := logging.FromContext(ctx)
+
+	clip, _, err := openPCMClip(file, header, s.chunkingConfig)
+	if err != nil {
+		logger.Debug("Not trimming silence from unreadable audio", "error", err)
+		return nil, 0, false
+	}
+
+	opts := vad.DefaultOptions()
+	opts.MinSilence = time.Duration(s.vadConfig.MaxPauseMs) * time.Millisecond
+	opts.Padding = time.Duration(s.vadConfig.PaddingMs) * time.Millisecond
+	spans, err := vad.Detect(clip, opts)
+	if err != nil {
+		logger.Warn("Voice activity detection failed", "error", err)
+		return nil, 0, false
+	}
+	if len(spans) == 0 {
+		return nil, 0, false
+	}
+
+	compacted, err := vad.Compact(clip, spans, opts.MinSilence)
+	if err != nil {
+		logger.Warn("Failed to trim silence", "error", err)
+		return nil, 0, false
+	}
+
+	saved := (clip.Duration() - compacted.Duration()).Seconds()
+	if saved < minSilenceTrimmed.Seconds() {
+		return nil, 0, false
+	}
+
+	logger.Debug("Trimmed silence before upload", "duration", clip.Duration().Seconds(), "trimmed", saved)
+	return compacted.WAV(), saved, true
+}
+
+// fileContentType returns the media type of an uploaded file, falling back to detection
+// by file extension when the part carries no specific Content-Type
+func fileContentType(header *multipart.FileHeader) string {
+	contentType := header.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && media.IsGeneric(mediaType) {
+		contentType = ""
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(strings.ToLower(filepath.Ext(header.Filename)))
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return strings.ToLower(mediaType)
+	}
+	return contentType
+}
+
 // isContentTypeAllowed checks if the content type is in the allowed list
 func (s *UploadService) isContentTypeAllowed(contentType string) bool {
 	// Handle multipart content types
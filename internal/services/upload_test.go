@@ -0,0 +1,76 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"qwen3-compatibility/internal/audio"
+	"qwen3-compatibility/internal/config"
+)
+
+func TestPrepareFileTrimsSilence(t *testing.T) {
+	// One second of tone between two seconds of silence on each side, at 16 kHz
+	pcm := pcmBytes(levelSpan{32000, 0}, levelSpan{16000, 8000}, levelSpan{32000, 0})
+	wav, err := io.ReadAll(testClip(t, levelSpan{32000, 0}, levelSpan{16000, 8000}, levelSpan{32000, 0}).WAV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		fileName    string
+		contentType string
+		data        []byte
+		keepSilence bool
+		wantName    string
+		wantType    string
+		wantTrimmed bool
+	}{
+		{"wav", "call.wav", "audio/wav", wav, false, "call.wav", "audio/wav", true},
+		{"pcm", "call.pcm", "audio/pcm", pcm, false, "call.wav", "audio/wav", true},
+		{"pcm keeping silence", "call.pcm", "audio/pcm", pcm, true, "call.pcm", "audio/pcm", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUploadService(nil,
+				&config.UploadConfig{MaxFileSize: 1 << 20, AllowedTypes: []string{"audio/wav", "audio/pcm"}},
+				&config.VADConfig{Enabled: true, MaxPauseMs: 1000, PaddingMs: 200},
+				&config.ChunkingConfig{PCMSampleRate: 16000, PCMChannels: 1},
+				nil)
+			header := &multipart.FileHeader{
+				Filename: tt.fileName,
+				Size:     int64(len(tt.data)),
+				Header:   textproto.MIMEHeader{"Content-Type": {tt.contentType}},
+			}
+
+			prepared, err := s.PrepareFile(context.Background(), sectionFile{io.NewSectionReader(bytes.NewReader(tt.data), 0, int64(len(tt.data)))}, header, "qwen3-asr-flash", UploadOptions{KeepSilence: tt.keepSilence})
+			if err != nil {
+				t.Fatalf("PrepareFile() error = %v", err)
+			}
+			defer func() { _ = prepared.Close() }()
+
+			if prepared.Name != tt.wantName || prepared.ContentType != tt.wantType {
+				t.Errorf("PrepareFile() = %s %s, want %s %s", prepared.Name, prepared.ContentType, tt.wantName, tt.wantType)
+			}
+			if trimmed := prepared.SilenceTrimmed > 0; trimmed != tt.wantTrimmed {
+				t.Fatalf("SilenceTrimmed = %v, want trimmed %v", prepared.SilenceTrimmed, tt.wantTrimmed)
+			}
+			if !tt.wantTrimmed {
+				return
+			}
+
+			clip, err := audio.OpenWAV(prepared.File, prepared.Size)
+			if err != nil {
+				t.Fatalf("trimmed audio is not WAV: %v", err)
+			}
+			if got := clip.Duration().Seconds(); got >= 2 {
+				t.Errorf("trimmed duration = %v s, want less than 2 s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/store"
+)
+
+var (
+	// ErrVocabularyReadOnly is returned when a vocabulary defined in the configuration is
+	// changed through the API
+	ErrVocabularyReadOnly = stderrors.New("vocabulary is defined in the configuration")
+	// ErrVocabularyStoreDisabled is returned when vocabularies are changed without a store
+	ErrVocabularyStoreDisabled = stderrors.New("vocabulary store is not configured")
+)
+
+// VocabularyService resolves named vocabulary lists and merges their terms into the
+// context the model is prompted with. Lists come from the configuration, which cannot
+// be changed at run time, and from a store managed through the admin API.
+type VocabularyService struct {
+	store  *store.VocabularyStore
+	config *config.VocabularyConfig
+}
+
+// NewVocabularyService creates the service. vocabularyStore may be nil, leaving only the
+// lists defined in the configuration.
+func NewVocabularyService(vocabularyStore *store.VocabularyStore, vocabularyConfig *config.VocabularyConfig) *VocabularyService {
+	return &VocabularyService{
+		store:  vocabularyStore,
+		config: vocabularyConfig,
+	}
+}
+
+// Get returns the vocabulary with the given ID. Lists in the configuration take
+// precedence over stored ones.
+func (s *VocabularyService) Get(id string) (*models.Vocabulary, error) {
+	if terms, ok := s.config.Lists[id]; ok {
+		return &models.Vocabulary{ID: id, Terms: normalizeTerms(terms), Source: models.VocabularySourceConfig}, nil
+	}
+	if s.store == nil {
+		return nil, store.ErrVocabularyNotFound
+	}
+
+	vocabulary, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	vocabulary.Source = models.VocabularySourceAPI
+	return vocabulary, nil
+}
+
+// List returns all vocabularies ordered by ID
+func (s *VocabularyService) List() []models.Vocabulary {
+	var vocabularies []models.Vocabulary
+	for id, terms := range s.config.Lists {
+		vocabularies = append(vocabularies, models.Vocabulary{ID: id, Terms: normalizeTerms(terms), Source: models.VocabularySourceConfig})
+	}
+	if s.store != nil {
+		for _, vocabulary := range s.store.List() {
+			if _, ok := s.config.Lists[vocabulary.ID]; ok {
+				continue
+			}
+			vocabulary.Source = models.VocabularySourceAPI
+			vocabularies = append(vocabularies, vocabulary)
+		}
+	}
+
+	sort.Slice(vocabularies, func(i, j int) bool {
+		return vocabularies[i].ID < vocabularies[j].ID
+	})
+	return vocabularies
+}
+
+// Put creates or replaces a stored vocabulary. Terms are trimmed and deduplicated, and
+// must fit the configured limits.
+func (s *VocabularyService) Put(id string, terms []string) (*models.Vocabulary, error) {
+	if s.store == nil {
+		return nil, ErrVocabularyStoreDisabled
+	}
+	if _, ok := s.config.Lists[id]; ok {
+		return nil, ErrVocabularyReadOnly
+	}
+
+	terms = normalizeTerms(terms)
+	if len(terms) > s.config.MaxTerms {
+		return nil, errors.NewValidationError(fmt.Sprintf("A vocabulary has at most %d terms", s.config.MaxTerms))
+	}
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) > s.config.MaxTermLength {
+			return nil, errors.NewValidationError(fmt.Sprintf("Term %q is longer than %d characters", term, s.config.MaxTermLength))
+		}
+	}
+
+	vocabulary, err := s.store.Put(id, terms)
+	if err != nil {
+		return nil, err
+	}
+	vocabulary.Source = models.VocabularySourceAPI
+	return vocabulary, nil
+}
+
+// Delete removes a stored vocabulary
+func (s *VocabularyService) Delete(id string) error {
+	if _, ok := s.config.Lists[id]; ok {
+		return ErrVocabularyReadOnly
+	}
+	if s.store == nil {
+		return store.ErrVocabularyNotFound
+	}
+	return s.store.Delete(id)
+}
+
+// Prompt merges the terms of the vocabularies into the prompt, after the caller's text.
+// Terms repeated across vocabularies or already in the prompt are added once, and terms
+// that would make the context longer than vocabulary.max_context_length are left out.
+// Unknown IDs are a validation error unless skipUnknown is set, as for the default
+// vocabularies of a key, which may have been deleted since.
+func (s *VocabularyService) Prompt(ctx context.Context, prompt string, ids []string, skipUnknown bool) (string, error) {
+	if len(ids) == 0 {
+		return prompt, nil
+	}
+	logger := logging.FromContext(ctx)
+
+	seen := make(map[string]bool)
+	lowerPrompt := strings.ToLower(prompt)
+	var terms []string
+	for _, id := range ids {
+		vocabulary, err := s.Get(id)
+		if stderrors.Is(err, store.ErrVocabularyNotFound) {
+			if skipUnknown {
+				logger.Warn("Default vocabulary not found", "vocabulary_id", id)
+				continue
+			}
+			return "", errors.NewValidationError("Unknown vocabulary_id: " + id)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		for _, term := range vocabulary.Terms {
+			key := strings.ToLower(term)
+			if seen[key] || strings.Contains(lowerPrompt, key) {
+				continue
+			}
+			seen[key] = true
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return prompt, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(prompt)
+	length := utf8.RuneCountInString(prompt)
+	added := 0
+	for _, term := range terms {
+		separator := ", "
+		if added == 0 {
+			separator = ""
+			if prompt != "" {
+				separator = "\n"
+			}
+		}
+		termLength := utf8.RuneCountInString(separator) + utf8.RuneCountInString(term)
+		if length+termLength > s.config.MaxContextLength {
+			break
+		}
+		b.WriteString(separator)
+		b.WriteString(term)
+		length += termLength
+		added++
+	}
+	if added < len(terms) {
+		logger.Warn("Vocabulary terms left out of the context",
+			"vocabulary_ids", ids,
+			"dropped", len(terms)-added,
+			"max_context_length", s.config.MaxContextLength,
+		)
+	}
+
+	return b.String(), nil
+}
+
+// normalizeTerms trims the terms and removes empty and repeated ones, comparing case
+// insensitively and keeping the first spelling
+func normalizeTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.Join(strings.Fields(term), " ")
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, term)
+	}
+	return normalized
+}
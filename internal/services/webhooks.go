@@ -0,0 +1,368 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"qwen3-compatibility/internal/config"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/store"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// Upper bound for the delay between two delivery attempts
+const maxWebhookBackoff = 5 * time.Minute
+
+var ErrDeadLetterNotFound = stderrors.New("dead letter not found")
+
+// ErrCallbackAddressBlocked is returned for deliveries to internal addresses that are
+// not in webhooks.allowed_hosts
+var ErrCallbackAddressBlocked = stderrors.New("callback address is not allowed")
+
+// WebhookService delivers job completion callbacks, retrying with exponential backoff
+// and recording deliveries that keep failing in the dead-letter store
+type WebhookService struct {
+	jobStore    *store.JobStore
+	deadLetters *store.DeadLetterStore
+	config      *config.WebhooksConfig
+	httpClient  *http.Client
+
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+func NewWebhookService(jobStore *store.JobStore, deadLetters *store.DeadLetterStore, webhooksConfig *config.WebhooksConfig) *WebhookService {
+	ctx, stop := context.WithCancel(context.Background())
+	return &WebhookService{
+		jobStore:    jobStore,
+		deadLetters: deadLetters,
+		config:      webhooksConfig,
+		httpClient: &http.Client{
+			Transport: otelhttp.NewTransport(newCallbackTransport(webhooksConfig.AllowedHosts)),
+			Timeout:   time.Duration(webhooksConfig.Timeout) * time.Second,
+		},
+		ctx:  ctx,
+		stop: stop,
+	}
+}
+
+// Stop aborts pending retries and waits for in-flight deliveries. Callbacks that are
+// still pending are delivered again on the next start.
+func (s *WebhookService) Stop(ctx context.Context) error {
+	s.stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Notify starts delivering the callback of a finished job in the background
+func (s *WebhookService) Notify(job *models.TranscriptionJob) {
+	if job.Callback == nil || job.Callback.Status != models.CallbackStatusPending || !job.Status.IsTerminal() {
+		return
+	}
+
+	s.wg.Add(1)
+	go s.deliver(job)
+}
+
+// ListDeadLetters returns the callbacks that could not be delivered
+func (s *WebhookService) ListDeadLetters() []models.WebhookDeadLetter {
+	return s.deadLetters.List()
+}
+
+// Redeliver makes one more delivery attempt for a dead-lettered callback. The dead
+// letter is removed when the attempt succeeds and updated when it fails.
+func (s *WebhookService) Redeliver(ctx context.Context, id string) (*models.WebhookDeadLetter, error) {
+	entry, err := s.deadLetters.Get(id)
+	if err != nil {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	statusCode, sendErr := s.send(ctx, entry.URL, entry.ID, entry.Payload)
+	entry.Attempts++
+	entry.LastStatusCode = statusCode
+
+	if sendErr != nil {
+		entry.LastError = sendErr.Error()
+		entry.FailedAt = time.Now().UTC()
+		if err := s.deadLetters.Add(entry); err != nil {
+			return nil, err
+		}
+		s.updateCallback(entry.JobID, func(callback *models.JobCallback) {
+			callback.Attempts++
+			callback.LastStatusCode = statusCode
+			callback.LastError = entry.LastError
+		})
+		return entry, sendErr
+	}
+
+	entry.LastError = ""
+	if err := s.deadLetters.Delete(entry.ID); err != nil {
+		return nil, err
+	}
+	s.updateCallback(entry.JobID, func(callback *models.JobCallback) {
+		callback.Status = models.CallbackStatusDelivered
+		callback.Attempts++
+		callback.LastStatusCode = statusCode
+		callback.LastError = ""
+		callback.DeliveredAt = time.Now().UTC()
+	})
+
+	return entry, nil
+}
+
+// deliver sends a job callback until it succeeds, attempts run out or the service stops
+func (s *WebhookService) deliver(job *models.TranscriptionJob) {
+	defer s.wg.Done()
+
+	logger := slog.Default().With("job_id", job.ID, "delivery_id", job.Callback.DeliveryID)
+
+	payload, err := webhookPayload(job)
+	if err != nil {
+		logger.Error("Failed to encode webhook payload", "error", err)
+		return
+	}
+
+	attempts := job.Callback.Attempts
+	backoff := time.Duration(s.config.InitialBackoff) * time.Second
+	for i := 0; i < attempts; i++ {
+		backoff = nextBackoff(backoff)
+	}
+
+	for {
+		statusCode, sendErr := s.send(s.ctx, job.Callback.URL, job.Callback.DeliveryID, payload)
+		if s.ctx.Err() != nil {
+			// Shutting down; the callback stays pending and is retried on the next start
+			return
+		}
+		attempts++
+
+		if sendErr == nil {
+			s.updateCallback(job.ID, func(callback *models.JobCallback) {
+				callback.Status = models.CallbackStatusDelivered
+				callback.Attempts = attempts
+				callback.LastStatusCode = statusCode
+				callback.LastError = ""
+				callback.DeliveredAt = time.Now().UTC()
+			})
+			logger.Info("Webhook delivered", "attempts", attempts)
+			return
+		}
+
+		logger.Warn("Webhook delivery failed", "attempt", attempts, "status", statusCode, "error", sendErr)
+
+		// Blocked addresses are not retried
+		if attempts >= s.config.MaxAttempts || stderrors.Is(sendErr, ErrCallbackAddressBlocked) {
+			s.updateCallback(job.ID, func(callback *models.JobCallback) {
+				callback.Status = models.CallbackStatusFailed
+				callback.Attempts = attempts
+				callback.LastStatusCode = statusCode
+				callback.LastError = sendErr.Error()
+			})
+			s.deadLetter(job, payload, attempts, statusCode, sendErr)
+			return
+		}
+
+		s.updateCallback(job.ID, func(callback *models.JobCallback) {
+			callback.Attempts = attempts
+			callback.LastStatusCode = statusCode
+			callback.LastError = sendErr.Error()
+		})
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// send makes a single signed delivery attempt and returns the response status code
+func (s *WebhookService) send(ctx context.Context, url, deliveryID string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "qwen3-compatibility-webhook")
+	req.Header.Set(WebhookIDHeader, deliveryID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(s.config.Secret, timestamp, payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *WebhookService) deadLetter(job *models.TranscriptionJob, payload []byte, attempts, statusCode int, sendErr error) {
+	entry := &models.WebhookDeadLetter{
+		ID:             job.Callback.DeliveryID,
+		JobID:          job.ID,
+		URL:            job.Callback.URL,
+		Payload:        payload,
+		Attempts:       attempts,
+		LastStatusCode: statusCode,
+		LastError:      sendErr.Error(),
+		CreatedAt:      job.CompletedAt,
+		FailedAt:       time.Now().UTC(),
+	}
+	if err := s.deadLetters.Add(entry); err != nil {
+		slog.Error("Failed to record dead-lettered webhook", "delivery_id", entry.ID, "job_id", job.ID, "error", err)
+		return
+	}
+
+	slog.Warn("Webhook dead-lettered", "delivery_id", entry.ID, "job_id", job.ID, "attempts", attempts)
+}
+
+// updateCallback records delivery progress on the job, if it still exists
+func (s *WebhookService) updateCallback(jobID string, fn func(callback *models.JobCallback)) {
+	_, err := s.jobStore.Update(jobID, func(job *models.TranscriptionJob) {
+		if job.Callback != nil {
+			callback := *job.Callback
+			fn(&callback)
+			job.Callback = &callback
+		}
+	})
+	if err != nil && !stderrors.Is(err, store.ErrJobNotFound) {
+		slog.Warn("Failed to update job callback status", "job_id", jobID, "error", err)
+	}
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<payload>" using secret
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookPayload builds the event sent for a finished job. The delivery ID doubles as the
+// event ID so receivers can deduplicate retries.
+func webhookPayload(job *models.TranscriptionJob) ([]byte, error) {
+	data := models.NewTranscriptionJobResponse(job)
+	data.Callback = nil
+
+	return json.Marshal(models.WebhookEvent{
+		ID:        job.Callback.DeliveryID,
+		Object:    "event",
+		Type:      "transcription.job." + string(job.Status),
+		CreatedAt: job.CompletedAt.Unix(),
+		Data:      data,
+	})
+}
+
+// callbackGuard refuses connections to internal addresses, except for allowed hosts
+type callbackGuard struct {
+	hosts    map[string]bool
+	prefixes []netip.Prefix
+}
+
+// newCallbackTransport returns a transport for webhook deliveries that only connects to
+// public addresses, or to the hosts, addresses and CIDR ranges in allowed. The addresses
+// are checked when connecting, after name resolution, so that a host name cannot
+// resolve to a public address when validated and to an internal one when dialed.
+func newCallbackTransport(allowed []string) *http.Transport {
+	guard := &callbackGuard{hosts: make(map[string]bool)}
+	for _, entry := range allowed {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			guard.prefixes = append(guard.prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			guard.prefixes = append(guard.prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		} else {
+			guard.hosts[strings.ToLower(entry)] = true
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	guarded := &net.Dialer{Timeout: dialer.Timeout, KeepAlive: dialer.KeepAlive, Control: guard.control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && guard.hosts[strings.ToLower(host)] {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+	return transport
+}
+
+// control checks the resolved address of a connection before it is made
+func (g *callbackGuard) control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCallbackAddressBlocked, address)
+	}
+	addr := addrPort.Addr().Unmap()
+	if !isInternalAddress(addr) {
+		return nil
+	}
+	for _, prefix := range g.prefixes {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is an internal address", ErrCallbackAddressBlocked, addr)
+}
+
+// isInternalAddress reports whether addr is a loopback, private, link-local or
+// unspecified address, which includes cloud metadata endpoints such as 169.254.169.254
+func isInternalAddress(addr netip.Addr) bool {
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsUnspecified()
+}
+
+func nextBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > maxWebhookBackoff {
+		return maxWebhookBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+func TestCallbackTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localhostURL := "http://localhost:" + serverURL.Port()
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		url         string
+		wantBlocked bool
+	}{
+		{name: "loopback address", url: server.URL, wantBlocked: true},
+		{name: "localhost", url: localhostURL, wantBlocked: true},
+		{name: "allowed address", allowed: []string{"127.0.0.1"}, url: server.URL},
+		{name: "allowed range", allowed: []string{"127.0.0.0/8", "::1/128"}, url: localhostURL},
+		{name: "allowed host name", allowed: []string{"LocalHost"}, url: localhostURL},
+		{name: "other host allowed", allowed: []string{"hooks.internal", "10.0.0.0/8"}, url: server.URL, wantBlocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: newCallbackTransport(tt.allowed)}
+			resp, err := client.Post(tt.url, "application/json", nil)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if blocked := stderrors.Is(err, ErrCallbackAddressBlocked); blocked != tt.wantBlocked {
+				t.Errorf("Post() error = %v, want blocked %v", err, tt.wantBlocked)
+			}
+			if !tt.wantBlocked && err != nil {
+				t.Errorf("Post() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestIsInternalAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"8.8.8.8", false},
+		{"172.32.0.1", false},
+		{"2001:4860:4860::8888", false},
+	}
+
+	for _, tt := range tests {
+		if got := isInternalAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isInternalAddress(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"qwen3-compatibility/internal/config"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores opaque objects by key. Implementations must be safe for concurrent use.
+type Storage interface {
+	// Put stores the content of r under key and returns the number of bytes written
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get opens the object stored under key. It returns ErrNotFound for unknown keys.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting an unknown key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage backend selected in the files configuration
+func New(filesConfig *config.FilesConfig) (Storage, error) {
+	switch filesConfig.Storage {
+	case "", "local":
+		return NewLocalStorage(filesConfig.Dir)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", filesConfig.Storage)
+	}
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"qwen3-compatibility/internal/models"
+)
+
+const batchIDPrefix = "batch_"
+
+var ErrBatchNotFound = errors.New("batch not found")
+
+// BatchStore persists batches under a local directory as <id>.json. Results of a batch
+// in progress are appended to <id>.<name>.jsonl files so that work survives restarts.
+type BatchStore struct {
+	mu      sync.RWMutex
+	dir     string
+	batches map[string]*models.Batch
+}
+
+func NewBatchStore(dir string) (*BatchStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create batch directory: %w", err)
+	}
+
+	s := &BatchStore{
+		dir:     dir,
+		batches: make(map[string]*models.Batch),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		var batch models.Batch
+		if _, err := readJSONFile(filepath.Join(dir, entry.Name()), &batch); err != nil {
+			return nil, fmt.Errorf("failed to load batch: %w", err)
+		}
+		if batch.ID != "" {
+			s.batches[batch.ID] = &batch
+		}
+	}
+
+	return s, nil
+}
+
+// NewID generates a new batch ID
+func (s *BatchStore) NewID() (string, error) {
+	return randomToken(batchIDPrefix, 12)
+}
+
+// Create persists a new batch
+func (s *BatchStore) Create(batch *models.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.saveLocked(batch); err != nil {
+		return err
+	}
+	stored := *batch
+	s.batches[batch.ID] = &stored
+
+	return nil
+}
+
+// Get returns a copy of the batch with the given ID
+func (s *BatchStore) Get(id string) (*models.Batch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	batch, ok := s.batches[id]
+	if !ok {
+		return nil, ErrBatchNotFound
+	}
+
+	result := *batch
+	return &result, nil
+}
+
+// List returns copies of all batches ordered by creation time, newest first
+func (s *BatchStore) List() []models.Batch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	batches := make([]models.Batch, 0, len(s.batches))
+	for _, batch := range s.batches {
+		batches = append(batches, *batch)
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i].CreatedAt.After(batches[j].CreatedAt)
+	})
+
+	return batches
+}
+
+// Update applies fn to the batch with the given ID and persists the result
+func (s *BatchStore) Update(id string, fn func(batch *models.Batch)) (*models.Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, ok := s.batches[id]
+	if !ok {
+		return nil, ErrBatchNotFound
+	}
+
+	updated := *batch
+	fn(&updated)
+
+	if err := s.saveLocked(&updated); err != nil {
+		return nil, err
+	}
+	*batch = updated
+
+	result := updated
+	return &result, nil
+}
+
+// ResultPath returns the path of a result file of the batch, e.g. "output" or "errors"
+func (s *BatchStore) ResultPath(id, name string) string {
+	return filepath.Join(s.dir, id+"."+name+".jsonl")
+}
+
+// RemoveResults deletes the result files of a batch
+func (s *BatchStore) RemoveResults(id string, names ...string) error {
+	for _, name := range names {
+		if err := os.Remove(s.ResultPath(id, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove batch results: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *BatchStore) saveLocked(batch *models.Batch) error {
+	if _, err := writeJSONFile(filepath.Join(s.dir, batch.ID+".json"), batch); err != nil {
+		return fmt.Errorf("failed to save batch: %w", err)
+	}
+	return nil
+}
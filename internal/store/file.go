@@ -0,0 +1,88 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// readJSONFile decodes the file at path into v and returns its modification time.
+// A missing file leaves v untouched and is not an error.
+func readJSONFile(path string, v interface{}) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(data) == 0 {
+		return info.ModTime(), nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return info.ModTime(), nil
+}
+
+// writeJSONFile atomically replaces the file at path with the JSON encoding of v
+// and returns the new modification time
+func writeJSONFile(path string, v interface{}) (time.Time, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return time.Time{}, err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return time.Time{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return time.Time{}, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return time.Time{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return time.Time{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// randomToken returns prefix followed by n random bytes encoded as hex
+func randomToken(prefix string, n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/storage"
+)
+
+const fileIDPrefix = "file-"
+
+var (
+	ErrFileNotFound = errors.New("file not found")
+	ErrFileTooLarge = errors.New("file too large")
+)
+
+// FileStore keeps the metadata of files uploaded through the Files API as <id>.json under
+// a local directory. File contents are kept in a pluggable storage backend.
+type FileStore struct {
+	mu       sync.RWMutex
+	dir      string
+	contents storage.Storage
+	files    map[string]*models.File
+}
+
+func NewFileStore(dir string, contents storage.Storage) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create file directory: %w", err)
+	}
+
+	s := &FileStore{
+		dir:      dir,
+		contents: contents,
+		files:    make(map[string]*models.File),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		var file models.File
+		if _, err := readJSONFile(filepath.Join(dir, entry.Name()), &file); err != nil {
+			return nil, fmt.Errorf("failed to load file: %w", err)
+		}
+		if file.ID != "" {
+			s.files[file.ID] = &file
+		}
+	}
+
+	return s, nil
+}
+
+// Create stores content as a new file. The ID and size of file are filled in.
+// Content larger than maxBytes is rejected with ErrFileTooLarge when maxBytes is positive.
+func (s *FileStore) Create(ctx context.Context, file *models.File, content io.Reader, maxBytes int64) error {
+	id, err := randomToken(fileIDPrefix, 12)
+	if err != nil {
+		return err
+	}
+	file.ID = id
+
+	reader := content
+	if maxBytes > 0 {
+		reader = io.LimitReader(content, maxBytes+1)
+	}
+	written, err := s.contents.Put(ctx, contentKey(id), reader)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if maxBytes > 0 && written > maxBytes {
+		_ = s.contents.Delete(ctx, contentKey(id))
+		return ErrFileTooLarge
+	}
+	file.Bytes = written
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.saveLocked(file); err != nil {
+		_ = s.contents.Delete(ctx, contentKey(id))
+		return err
+	}
+	stored := *file
+	s.files[id] = &stored
+
+	return nil
+}
+
+// Get returns a copy of the file metadata with the given ID
+func (s *FileStore) Get(id string) (*models.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	file, ok := s.files[id]
+	if !ok {
+		return nil, ErrFileNotFound
+	}
+
+	result := *file
+	return &result, nil
+}
+
+// Open opens the content of the file with the given ID
+func (s *FileStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	content, err := s.contents.Get(ctx, contentKey(id))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return content, nil
+}
+
+// List returns copies of all files ordered by creation time
+func (s *FileStore) List() []models.File {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files := make([]models.File, 0, len(s.files))
+	for _, file := range s.files {
+		files = append(files, *file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CreatedAt.Before(files[j].CreatedAt)
+	})
+
+	return files
+}
+
+// Update applies fn to the metadata of the file with the given ID and persists the result
+func (s *FileStore) Update(id string, fn func(file *models.File)) (*models.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[id]
+	if !ok {
+		return nil, ErrFileNotFound
+	}
+
+	updated := *file
+	fn(&updated)
+
+	if err := s.saveLocked(&updated); err != nil {
+		return nil, err
+	}
+	*file = updated
+
+	result := updated
+	return &result, nil
+}
+
+// Delete removes a file and its content
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.files[id]; !ok {
+		return ErrFileNotFound
+	}
+
+	if err := s.contents.Delete(ctx, contentKey(id)); err != nil {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+	if err := os.Remove(s.metaPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+	delete(s.files, id)
+
+	return nil
+}
+
+func (s *FileStore) saveLocked(file *models.File) error {
+	if _, err := writeJSONFile(s.metaPath(file.ID), file); err != nil {
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) metaPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// contentKey is the storage key of a file's content
+func contentKey(id string) string {
+	return id + ".data"
+}
@@ -0,0 +1,191 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"qwen3-compatibility/internal/models"
+)
+
+const jobIDPrefix = "job_"
+
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStore persists transcription jobs and their audio under a local directory.
+// Each job is stored as <id>.json next to its audio file <id>.audio.
+type JobStore struct {
+	mu   sync.RWMutex
+	dir  string
+	jobs map[string]*models.TranscriptionJob
+}
+
+func NewJobStore(dir string) (*JobStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create job directory: %w", err)
+	}
+
+	s := &JobStore{
+		dir:  dir,
+		jobs: make(map[string]*models.TranscriptionJob),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		var job models.TranscriptionJob
+		if _, err := readJSONFile(filepath.Join(dir, entry.Name()), &job); err != nil {
+			return nil, fmt.Errorf("failed to load job: %w", err)
+		}
+		if job.ID != "" {
+			s.jobs[job.ID] = &job
+		}
+	}
+
+	return s, nil
+}
+
+// NewID generates a new job ID
+func (s *JobStore) NewID() (string, error) {
+	return randomToken(jobIDPrefix, 12)
+}
+
+// Create stores the audio for a new job and persists the job
+func (s *JobStore) Create(job *models.TranscriptionJob, audio io.Reader) error {
+	audioPath := filepath.Join(s.dir, job.ID+".audio")
+
+	f, err := os.OpenFile(audioPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create job audio file: %w", err)
+	}
+	if _, err := io.Copy(f, audio); err != nil {
+		_ = f.Close()
+		_ = os.Remove(audioPath)
+		return fmt.Errorf("failed to write job audio file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(audioPath)
+		return fmt.Errorf("failed to write job audio file: %w", err)
+	}
+	job.AudioPath = audioPath
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.saveLocked(job); err != nil {
+		_ = os.Remove(audioPath)
+		return err
+	}
+	s.jobs[job.ID] = job
+
+	return nil
+}
+
+// Get returns a copy of the job with the given ID
+func (s *JobStore) Get(id string) (*models.TranscriptionJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	result := *job
+	return &result, nil
+}
+
+// List returns copies of all jobs ordered by creation time
+func (s *JobStore) List() []models.TranscriptionJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]models.TranscriptionJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs
+}
+
+// Update applies fn to the job with the given ID and persists the result
+func (s *JobStore) Update(id string, fn func(job *models.TranscriptionJob)) (*models.TranscriptionJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	updated := *job
+	fn(&updated)
+
+	if err := s.saveLocked(&updated); err != nil {
+		return nil, err
+	}
+	*job = updated
+
+	result := updated
+	return &result, nil
+}
+
+// RemoveAudio deletes the stored audio of a job that no longer needs it
+func (s *JobStore) RemoveAudio(id string) error {
+	s.mu.RLock()
+	job, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	if err := os.Remove(job.AudioPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove job audio: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a job and its audio
+func (s *JobStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	if err := os.Remove(job.AudioPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove job audio: %w", err)
+	}
+	if err := os.Remove(s.jobPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove job: %w", err)
+	}
+	delete(s.jobs, id)
+
+	return nil
+}
+
+func (s *JobStore) saveLocked(job *models.TranscriptionJob) error {
+	if _, err := writeJSONFile(s.jobPath(job.ID), job); err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	return nil
+}
+
+func (s *JobStore) jobPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
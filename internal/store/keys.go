@@ -0,0 +1,288 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"qwen3-compatibility/internal/models"
+)
+
+const (
+	virtualKeyIDPrefix     = "vk_"
+	virtualKeySecretPrefix = "sk-qc-"
+	// Number of secret characters kept in KeyPrefix for display
+	visibleSecretChars = 12
+)
+
+var ErrKeyNotFound = errors.New("virtual key not found")
+
+// KeyStore keeps virtual API keys in memory and persists them to a local JSON file.
+// Changes made to the file by other processes are picked up on the next lookup.
+type KeyStore struct {
+	mu      sync.RWMutex
+	path    string
+	keys    map[string]*models.VirtualKey
+	byHash  map[string]string
+	modTime time.Time
+}
+
+func NewKeyStore(path string) (*KeyStore, error) {
+	s := &KeyStore{
+		path:   path,
+		keys:   make(map[string]*models.VirtualKey),
+		byHash: make(map[string]string),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Authenticate returns the virtual key matching the given secret
+func (s *KeyStore) Authenticate(secret string) (*models.VirtualKey, bool) {
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byHash[hashSecret(secret)]
+	if !ok {
+		return nil, false
+	}
+
+	key := *s.keys[id]
+	return &key, true
+}
+
+// Create stores a new virtual key and returns it together with its plaintext secret
+func (s *KeyStore) Create(req *models.CreateVirtualKeyRequest) (*models.VirtualKey, string, error) {
+	id, err := randomToken(virtualKeyIDPrefix, 12)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomToken(virtualKeySecretPrefix, 24)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC()
+	key := &models.VirtualKey{
+		ID:            id,
+		Name:          req.Name,
+		KeyPrefix:     secret[:len(virtualKeySecretPrefix)+visibleSecretChars],
+		KeyHash:       hashSecret(secret),
+		UpstreamKey:   req.UpstreamKey,
+		AllowedModels: req.AllowedModels,
+		Limits:        req.Limits,
+		CallbackURL:   req.CallbackURL,
+		VocabularyIDs: req.VocabularyIDs,
+		DictionaryIDs: req.DictionaryIDs,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = key
+	s.byHash[key.KeyHash] = key.ID
+	if err := s.saveLocked(); err != nil {
+		delete(s.keys, key.ID)
+		delete(s.byHash, key.KeyHash)
+		return nil, "", err
+	}
+
+	result := *key
+	return &result, secret, nil
+}
+
+// Get returns the virtual key with the given ID
+func (s *KeyStore) Get(id string) (*models.VirtualKey, error) {
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	result := *key
+	return &result, nil
+}
+
+// List returns all virtual keys ordered by creation time
+func (s *KeyStore) List() []models.VirtualKey {
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]models.VirtualKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys
+}
+
+// Update applies fn to the virtual key with the given ID and persists the result
+func (s *KeyStore) Update(id string, fn func(key *models.VirtualKey)) (*models.VirtualKey, error) {
+	s.reloadIfChanged()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	previous := *key
+	fn(key)
+	key.UpdatedAt = time.Now().UTC()
+
+	if err := s.saveLocked(); err != nil {
+		*key = previous
+		return nil, err
+	}
+
+	result := *key
+	return &result, nil
+}
+
+// Rotate replaces the secret of a virtual key and returns the new plaintext secret
+func (s *KeyStore) Rotate(id string) (*models.VirtualKey, string, error) {
+	secret, err := randomToken(virtualKeySecretPrefix, 24)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.reloadIfChanged()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, "", ErrKeyNotFound
+	}
+
+	previous := *key
+	now := time.Now().UTC()
+	key.KeyPrefix = secret[:len(virtualKeySecretPrefix)+visibleSecretChars]
+	key.KeyHash = hashSecret(secret)
+	key.UpdatedAt = now
+	key.RotatedAt = now
+
+	delete(s.byHash, previous.KeyHash)
+	s.byHash[key.KeyHash] = key.ID
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.byHash, key.KeyHash)
+		*key = previous
+		s.byHash[key.KeyHash] = key.ID
+		return nil, "", err
+	}
+
+	result := *key
+	return &result, secret, nil
+}
+
+// Delete removes the virtual key with the given ID
+func (s *KeyStore) Delete(id string) error {
+	s.reloadIfChanged()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	delete(s.keys, id)
+	delete(s.byHash, key.KeyHash)
+
+	if err := s.saveLocked(); err != nil {
+		s.keys[id] = key
+		s.byHash[key.KeyHash] = id
+		return err
+	}
+
+	return nil
+}
+
+// load reads the store file, treating a missing file as an empty store
+func (s *KeyStore) load() error {
+	var keys []*models.VirtualKey
+	modTime, err := readJSONFile(s.path, &keys)
+	if err != nil {
+		return fmt.Errorf("failed to load key store: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = make(map[string]*models.VirtualKey, len(keys))
+	s.byHash = make(map[string]string, len(keys))
+	for _, key := range keys {
+		s.keys[key.ID] = key
+		s.byHash[key.KeyHash] = key.ID
+	}
+	s.modTime = modTime
+
+	return nil
+}
+
+// reloadIfChanged reloads the store when the file was modified outside this process
+func (s *KeyStore) reloadIfChanged() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return
+	}
+
+	s.mu.RLock()
+	changed := info.ModTime().After(s.modTime)
+	s.mu.RUnlock()
+
+	if changed {
+		_ = s.load()
+	}
+}
+
+// saveLocked persists the store. The caller must hold the write lock.
+func (s *KeyStore) saveLocked() error {
+	keys := make([]*models.VirtualKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	modTime, err := writeJSONFile(s.path, keys)
+	if err != nil {
+		return fmt.Errorf("failed to save key store: %w", err)
+	}
+	s.modTime = modTime
+
+	return nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
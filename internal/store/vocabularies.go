@@ -0,0 +1,180 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"qwen3-compatibility/internal/models"
+)
+
+var ErrVocabularyNotFound = errors.New("vocabulary not found")
+
+// VocabularyStore keeps vocabularies managed through the admin API in memory and persists
+// them to a local JSON file. Changes made to the file by other processes are picked up on
+// the next lookup.
+type VocabularyStore struct {
+	mu           sync.RWMutex
+	path         string
+	vocabularies map[string]*models.Vocabulary
+	modTime      time.Time
+}
+
+func NewVocabularyStore(path string) (*VocabularyStore, error) {
+	s := &VocabularyStore{
+		path:         path,
+		vocabularies: make(map[string]*models.Vocabulary),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get returns the vocabulary with the given ID
+func (s *VocabularyStore) Get(id string) (*models.Vocabulary, error) {
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vocabulary, ok := s.vocabularies[id]
+	if !ok {
+		return nil, ErrVocabularyNotFound
+	}
+
+	result := *vocabulary
+	return &result, nil
+}
+
+// List returns all vocabularies ordered by ID
+func (s *VocabularyStore) List() []models.Vocabulary {
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vocabularies := make([]models.Vocabulary, 0, len(s.vocabularies))
+	for _, vocabulary := range s.vocabularies {
+		vocabularies = append(vocabularies, *vocabulary)
+	}
+	sort.Slice(vocabularies, func(i, j int) bool {
+		return vocabularies[i].ID < vocabularies[j].ID
+	})
+
+	return vocabularies
+}
+
+// Put creates the vocabulary with the given ID or replaces its terms
+func (s *VocabularyStore) Put(id string, terms []string) (*models.Vocabulary, error) {
+	s.reloadIfChanged()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	previous, existed := s.vocabularies[id]
+	vocabulary := &models.Vocabulary{
+		ID:        id,
+		Terms:     terms,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if existed {
+		vocabulary.CreatedAt = previous.CreatedAt
+	}
+
+	s.vocabularies[id] = vocabulary
+	if err := s.saveLocked(); err != nil {
+		if existed {
+			s.vocabularies[id] = previous
+		} else {
+			delete(s.vocabularies, id)
+		}
+		return nil, err
+	}
+
+	result := *vocabulary
+	return &result, nil
+}
+
+// Delete removes the vocabulary with the given ID
+func (s *VocabularyStore) Delete(id string) error {
+	s.reloadIfChanged()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vocabulary, ok := s.vocabularies[id]
+	if !ok {
+		return ErrVocabularyNotFound
+	}
+
+	delete(s.vocabularies, id)
+	if err := s.saveLocked(); err != nil {
+		s.vocabularies[id] = vocabulary
+		return err
+	}
+
+	return nil
+}
+
+// load reads the store file, treating a missing file as an empty store
+func (s *VocabularyStore) load() error {
+	var vocabularies []*models.Vocabulary
+	modTime, err := readJSONFile(s.path, &vocabularies)
+	if err != nil {
+		return fmt.Errorf("failed to load vocabulary store: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vocabularies = make(map[string]*models.Vocabulary, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		s.vocabularies[vocabulary.ID] = vocabulary
+	}
+	s.modTime = modTime
+
+	return nil
+}
+
+// reloadIfChanged reloads the store when the file was modified outside this process
+func (s *VocabularyStore) reloadIfChanged() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return
+	}
+
+	s.mu.RLock()
+	changed := info.ModTime().After(s.modTime)
+	s.mu.RUnlock()
+
+	if changed {
+		_ = s.load()
+	}
+}
+
+// saveLocked persists the store. The caller must hold the write lock.
+func (s *VocabularyStore) saveLocked() error {
+	vocabularies := make([]*models.Vocabulary, 0, len(s.vocabularies))
+	for _, vocabulary := range s.vocabularies {
+		vocabularies = append(vocabularies, vocabulary)
+	}
+	sort.Slice(vocabularies, func(i, j int) bool {
+		return vocabularies[i].ID < vocabularies[j].ID
+	})
+
+	modTime, err := writeJSONFile(s.path, vocabularies)
+	if err != nil {
+		return fmt.Errorf("failed to save vocabulary store: %w", err)
+	}
+	s.modTime = modTime
+
+	return nil
+}
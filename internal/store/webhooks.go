@@ -0,0 +1,129 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"qwen3-compatibility/internal/models"
+)
+
+const webhookDeliveryIDPrefix = "whd_"
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetterStore records webhook callbacks that could not be delivered, persisted to a
+// local JSON file so they can be inspected and redelivered later
+type DeadLetterStore struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[string]*models.WebhookDeadLetter
+}
+
+func NewDeadLetterStore(path string) (*DeadLetterStore, error) {
+	s := &DeadLetterStore{
+		path:    path,
+		entries: make(map[string]*models.WebhookDeadLetter),
+	}
+
+	var entries []*models.WebhookDeadLetter
+	if _, err := readJSONFile(path, &entries); err != nil {
+		return nil, fmt.Errorf("failed to load dead letters: %w", err)
+	}
+	for _, entry := range entries {
+		s.entries[entry.ID] = entry
+	}
+
+	return s, nil
+}
+
+// NewDeliveryID generates a new webhook delivery ID
+func NewDeliveryID() (string, error) {
+	return randomToken(webhookDeliveryIDPrefix, 12)
+}
+
+// Add records a failed delivery, replacing an earlier record with the same ID
+func (s *DeadLetterStore) Add(entry *models.WebhookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.entries[entry.ID]
+	stored := *entry
+	s.entries[entry.ID] = &stored
+	if err := s.saveLocked(); err != nil {
+		if existed {
+			s.entries[entry.ID] = previous
+		} else {
+			delete(s.entries, entry.ID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Get returns a copy of the dead letter with the given ID
+func (s *DeadLetterStore) Get(id string) (*models.WebhookDeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	result := *entry
+	return &result, nil
+}
+
+// List returns copies of all dead letters ordered by failure time
+func (s *DeadLetterStore) List() []models.WebhookDeadLetter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]models.WebhookDeadLetter, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FailedAt.Before(entries[j].FailedAt)
+	})
+
+	return entries
+}
+
+// Delete removes a dead letter
+func (s *DeadLetterStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+
+	delete(s.entries, id)
+	if err := s.saveLocked(); err != nil {
+		s.entries[id] = entry
+		return err
+	}
+
+	return nil
+}
+
+func (s *DeadLetterStore) saveLocked() error {
+	entries := make([]*models.WebhookDeadLetter, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FailedAt.Before(entries[j].FailedAt)
+	})
+
+	if _, err := writeJSONFile(s.path, entries); err != nil {
+		return fmt.Errorf("failed to save dead letters: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+// Package transcode converts audio and video files to mono speech audio with a local ffmpeg
+package transcode
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"qwen3-compatibility/internal/config"
+)
+
+var ErrUnavailable = stderrors.New("ffmpeg is not available")
+
+// Codecs of the transcoded audio
+const (
+	CodecFLAC = "flac"
+	CodecOpus = "opus"
+)
+
+const (
+	// Opus bitrate for mono speech
+	opusBitrate = "32k"
+	// Bytes of ffmpeg error output kept for error messages
+	maxStderr = 4096
+)
+
+// Transcoder runs ffmpeg to convert files to the configured codec and sample rate
+type Transcoder struct {
+	path       string
+	codec      string
+	sampleRate int
+	timeout    time.Duration
+}
+
+// New returns a transcoder for the ffmpeg binary named by the config. It returns
+// ErrUnavailable when the binary cannot be found.
+func New(transcodeConfig *config.TranscodeConfig) (*Transcoder, error) {
+	path, err := exec.LookPath(transcodeConfig.FFmpegPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return &Transcoder{
+		path:       path,
+		codec:      transcodeConfig.Codec,
+		sampleRate: transcodeConfig.SampleRate,
+		timeout:    time.Duration(transcodeConfig.Timeout) * time.Second,
+	}, nil
+}
+
+// Output is a transcoded file in a temporary directory, removed by Close
+type Output struct {
+	*os.File
+	Size        int64
+	ContentType string
+	// Extension is the file name extension of the codec, including the dot
+	Extension string
+}
+
+// Close closes and removes the transcoded file
+func (o *Output) Close() error {
+	err := o.File.Close()
+	_ = os.Remove(o.File.Name())
+	return err
+}
+
+// ContentType returns the content type of transcoded files
+func (t *Transcoder) ContentType() string {
+	if t.codec == CodecOpus {
+		return "audio/ogg"
+	}
+	return "audio/flac"
+}
+
+// SampleRate returns the sample rate of transcoded files
+func (t *Transcoder) SampleRate() int {
+	return t.sampleRate
+}
+
+// Transcode converts the first audio stream of the size bytes at input to mono audio at
+// the configured sample rate and codec. Video streams are dropped. The run is aborted
+// after the configured timeout.
+func (t *Transcoder) Transcode(ctx context.Context, input io.ReaderAt, size int64) (*Output, error) {
+	inputPath, cleanup, err := inputFile(input, size)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	extension := ".flac"
+	format := []string{"-c:a", "flac", "-f", "flac"}
+	if t.codec == CodecOpus {
+		extension = ".ogg"
+		format = []string{"-c:a", "libopus", "-b:a", opusBitrate, "-application", "voip", "-f", "ogg"}
+	}
+
+	output, err := os.CreateTemp("", "qwen3-transcode-*"+extension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcode output: %w", err)
+	}
+	result := &Output{File: output, ContentType: t.ContentType(), Extension: extension}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	args := []string{
+		"-nostdin", "-hide_banner", "-loglevel", "error", "-y",
+		"-i", inputPath,
+		"-map", "0:a:0", "-vn", "-sn", "-dn",
+		"-ac", "1", "-ar", strconv.Itoa(t.sampleRate),
+	}
+	args = append(args, format...)
+	args = append(args, output.Name())
+
+	cmd := exec.CommandContext(ctx, t.path, args...)
+	// Do not wait for children of a killed ffmpeg that still hold the error output open
+	cmd.WaitDelay = time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &limitedWriter{buf: &stderr, limit: maxStderr}
+	if err := cmd.Run(); err != nil {
+		_ = result.Close()
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffmpeg timed out after %s: %w", t.timeout, ctx.Err())
+		}
+		return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	info, err := output.Stat()
+	if err != nil {
+		_ = result.Close()
+		return nil, fmt.Errorf("failed to read transcode output: %w", err)
+	}
+	if info.Size() == 0 {
+		_ = result.Close()
+		return nil, fmt.Errorf("ffmpeg produced no audio")
+	}
+	result.Size = info.Size()
+	return result, nil
+}
+
+// FileName replaces the extension of name with the extension of the output
+func (o *Output) FileName(name string) string {
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if base == "" || base == "." {
+		base = "audio"
+	}
+	return base + o.Extension
+}
+
+// inputFile returns the path of a file holding the input, copying it to a temporary file
+// unless it already is one
+func inputFile(input io.ReaderAt, size int64) (string, func(), error) {
+	if file, ok := input.(*os.File); ok {
+		return file.Name(), func() {}, nil
+	}
+
+	tmp, err := os.CreateTemp("", "qwen3-transcode-input-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to buffer transcode input: %w", err)
+	}
+	cleanup := func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+	if _, err := io.Copy(tmp, io.NewSectionReader(input, 0, size)); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to buffer transcode input: %w", err)
+	}
+	return tmp.Name(), cleanup, nil
+}
+
+// limitedWriter keeps the first limit bytes written to it and discards the rest
+type limitedWriter struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if room := w.limit - w.buf.Len(); room > 0 {
+		w.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,143 @@
+// Package vad finds speech in PCM audio with an energy and zero-crossing voice activity
+// detector, and removes the silence around it
+package vad
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"qwen3-compatibility/internal/audio"
+)
+
+const (
+	// Levels below this RMS, about -46 dBFS, are always silence
+	minSpeechLevel = 0.005
+	// The speech threshold is this many times the noise floor of the recording, but at most
+	// a fraction of the speech level for recordings without pauses
+	noiseFloorFactor  = 3
+	speechLevelFactor = 0.25
+	// Quiet windows above this fraction of the speech threshold are still speech when their
+	// zero-crossing rate is high, so that unvoiced consonants such as "s" and "f" are kept
+	unvoicedLevelFactor = 0.5
+	unvoicedCrossings   = 0.25
+)
+
+// Options tune the detector
+type Options struct {
+	// Window is the resolution of the analysis
+	Window time.Duration
+	// MinSilence is the shortest pause that separates two spans of speech
+	MinSilence time.Duration
+	// MinSpeech is the shortest span kept; shorter bursts are treated as noise
+	MinSpeech time.Duration
+	// Padding is the audio kept before and after each span so that words are not clipped
+	Padding time.Duration
+}
+
+// DefaultOptions returns options suited to conversational speech
+func DefaultOptions() Options {
+	return Options{
+		Window:     30 * time.Millisecond,
+		MinSilence: 500 * time.Millisecond,
+		MinSpeech:  250 * time.Millisecond,
+		Padding:    200 * time.Millisecond,
+	}
+}
+
+// Span is the frames [Start, End) of a clip
+type Span struct {
+	Start int64
+	End   int64
+}
+
+// Detect returns the padded spans of speech in the clip in order, without overlaps. A
+// window is speech when it is louder than a multiple of the noise floor, estimated as the
+// 10th percentile of the levels, or slightly quieter with a high zero-crossing rate.
+func Detect(clip *audio.Clip, opts Options) ([]Span, error) {
+	stats, err := clip.Analyze(opts.Window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze audio: %w", err)
+	}
+	if len(stats) == 0 {
+		return nil, nil
+	}
+
+	levels := make([]float64, len(stats))
+	for i, window := range stats {
+		levels[i] = window.RMS
+	}
+	slices.Sort(levels)
+	noiseFloor, speechLevel := levels[len(levels)/10], levels[len(levels)*9/10]
+	threshold := max(minSpeechLevel, min(noiseFloor*noiseFloorFactor, speechLevel*speechLevelFactor))
+
+	minSilence := windows(opts.MinSilence, opts.Window)
+	minSpeech := windows(opts.MinSpeech, opts.Window)
+
+	// Runs of speech windows, joined across short pauses
+	var runs [][2]int
+	for i, window := range stats {
+		voiced := window.RMS >= threshold
+		unvoiced := window.RMS >= threshold*unvoicedLevelFactor && window.ZeroCrossingRate >= unvoicedCrossings
+		if !voiced && !unvoiced {
+			continue
+		}
+		if n := len(runs); n > 0 && i-runs[n-1][1] <= minSilence {
+			runs[n-1][1] = i + 1
+		} else {
+			runs = append(runs, [2]int{i, i + 1})
+		}
+	}
+
+	windowFrames := clip.FrameAt(opts.Window)
+	padding := clip.FrameAt(opts.Padding)
+	var spans []Span
+	for _, run := range runs {
+		if run[1]-run[0] < minSpeech {
+			continue
+		}
+
+		start := max(0, int64(run[0])*windowFrames-padding)
+		end := min(clip.Frames(), int64(run[1])*windowFrames+padding)
+		if n := len(spans); n > 0 && start < spans[n-1].End {
+			start = spans[n-1].End
+		}
+		if start < end {
+			spans = append(spans, Span{Start: start, End: end})
+		}
+	}
+
+	return spans, nil
+}
+
+// Compact returns the clip with the audio before the first span and after the last span
+// removed, and the pauses between spans shortened to at most maxPause. The clip is
+// returned unchanged when spans is empty.
+func Compact(clip *audio.Clip, spans []Span, maxPause time.Duration) (*audio.Clip, error) {
+	if len(spans) == 0 {
+		return clip, nil
+	}
+
+	// Half of the kept pause stays after the span before it and half before the span after
+	// it, so that the cut falls in the middle of the silence
+	halfPause := clip.FrameAt(maxPause) / 2
+	var parts []*audio.Clip
+	start, end := spans[0].Start, spans[0].End
+	for _, span := range spans[1:] {
+		if span.Start-end <= 2*halfPause {
+			end = span.End
+			continue
+		}
+		parts = append(parts, clip.Slice(start, end+halfPause))
+		start, end = span.Start-halfPause, span.End
+	}
+	parts = append(parts, clip.Slice(start, end))
+
+	return audio.Join(parts...)
+}
+
+// windows returns the number of analysis windows covering d
+func windows(d, window time.Duration) int {
+	return int(math.Ceil(float64(d) / float64(window)))
+}
@@ -0,0 +1,385 @@
+package zhconv
+
+// Phrases choose the counterpart of characters whose conversion depends on the word they
+// are part of, such as 发 in 头发 (頭髮) and 发展 (發展). They take precedence over the
+// character tables, longest match first.
+
+// traditionalCharOverrides replace the character table where the Unihan default is the
+// rarer reading in speech
+var traditionalCharOverrides = map[rune]rune{
+	'里': '裡',
+	'余': '餘',
+}
+
+var simplifiedToTraditionalPhrases = map[string]string{
+	"头发":   "頭髮",
+	"理发":   "理髮",
+	"发型":   "髮型",
+	"白发":   "白髮",
+	"毛发":   "毛髮",
+	"假发":   "假髮",
+	"染发":   "染髮",
+	"洗发":   "洗髮",
+	"脱发":   "脫髮",
+	"剪发":   "剪髮",
+	"护发":   "護髮",
+	"发廊":   "髮廊",
+	"发夹":   "髮夾",
+	"发际":   "髮際",
+	"短发":   "短髮",
+	"长发":   "長髮",
+	"卷发":   "捲髮",
+	"秀发":   "秀髮",
+	"黑发":   "黑髮",
+	"发丝":   "髮絲",
+	"金发":   "金髮",
+	"皇后":   "皇后",
+	"王后":   "王后",
+	"太后":   "太后",
+	"母后":   "母后",
+	"后妃":   "后妃",
+	"影后":   "影后",
+	"天后":   "天后",
+	"后土":   "后土",
+	"干部":   "幹部",
+	"干活":   "幹活",
+	"干什么":  "幹什麼",
+	"干嘛":   "幹嘛",
+	"干吗":   "幹嗎",
+	"能干":   "能幹",
+	"树干":   "樹幹",
+	"骨干":   "骨幹",
+	"主干":   "主幹",
+	"才干":   "才幹",
+	"干事":   "幹事",
+	"干劲":   "幹勁",
+	"干练":   "幹練",
+	"实干":   "實幹",
+	"苦干":   "苦幹",
+	"干线":   "幹線",
+	"躯干":   "軀幹",
+	"干道":   "幹道",
+	"干掉":   "幹掉",
+	"干涉":   "干涉",
+	"干扰":   "干擾",
+	"干预":   "干預",
+	"相干":   "相干",
+	"若干":   "若干",
+	"干戈":   "干戈",
+	"干支":   "干支",
+	"天干":   "天干",
+	"公里":   "公里",
+	"英里":   "英里",
+	"海里":   "海里",
+	"千里":   "千里",
+	"万里":   "萬里",
+	"百里":   "百里",
+	"里程":   "里程",
+	"邻里":   "鄰里",
+	"故里":   "故里",
+	"乡里":   "鄉里",
+	"里长":   "里長",
+	"华里":   "華里",
+	"阿里":   "阿里",
+	"马里":   "馬里",
+	"里约":   "里約",
+	"里斯本":  "里斯本",
+	"克里":   "克里",
+	"里根":   "里根",
+	"斯里兰卡": "斯里蘭卡",
+	"面条":   "麵條",
+	"面包":   "麵包",
+	"面粉":   "麵粉",
+	"拉面":   "拉麵",
+	"方便面":  "方便麵",
+	"泡面":   "泡麵",
+	"炒面":   "炒麵",
+	"汤面":   "湯麵",
+	"面食":   "麵食",
+	"凉面":   "涼麵",
+	"面馆":   "麵館",
+	"挂面":   "掛麵",
+	"牛肉面":  "牛肉麵",
+	"面团":   "麵團",
+	"关系":   "關係",
+	"联系":   "聯繫",
+	"维系":   "維繫",
+	"系鞋带":  "繫鞋帶",
+	"日历":   "日曆",
+	"历法":   "曆法",
+	"农历":   "農曆",
+	"阳历":   "陽曆",
+	"阴历":   "陰曆",
+	"公历":   "公曆",
+	"挂历":   "掛曆",
+	"台历":   "檯曆",
+	"月历":   "月曆",
+	"钟情":   "鍾情",
+	"钟爱":   "鍾愛",
+	"复杂":   "複雜",
+	"重复":   "重複",
+	"复制":   "複製",
+	"复印":   "複印",
+	"复数":   "複數",
+	"复习":   "複習",
+	"复合":   "複合",
+	"繁复":   "繁複",
+	"复式":   "複式",
+	"复诊":   "複診",
+	"复查":   "複查",
+	"复核":   "複核",
+	"复述":   "複述",
+	"复赛":   "複賽",
+	"复选":   "複選",
+	"复姓":   "複姓",
+	"答复":   "答覆",
+	"回复":   "回覆",
+	"反复":   "反覆",
+	"制造":   "製造",
+	"制作":   "製作",
+	"制品":   "製品",
+	"制成":   "製成",
+	"研制":   "研製",
+	"绘制":   "繪製",
+	"录制":   "錄製",
+	"定制":   "定製",
+	"缝制":   "縫製",
+	"制图":   "製圖",
+	"制片":   "製片",
+	"制药":   "製藥",
+	"监制":   "監製",
+	"轻松":   "輕鬆",
+	"放松":   "放鬆",
+	"松开":   "鬆開",
+	"宽松":   "寬鬆",
+	"松懈":   "鬆懈",
+	"蓬松":   "蓬鬆",
+	"松散":   "鬆散",
+	"松动":   "鬆動",
+	"松弛":   "鬆弛",
+	"松了":   "鬆了",
+	"松口气":  "鬆口氣",
+	"稀松":   "稀鬆",
+	"胡须":   "鬍鬚",
+	"触须":   "觸鬚",
+	"胡子":   "鬍子",
+	"冲水":   "沖水",
+	"冲洗":   "沖洗",
+	"冲泡":   "沖泡",
+	"冲凉":   "沖涼",
+	"冲咖啡":  "沖咖啡",
+	"冲茶":   "沖茶",
+	"冲刷":   "沖刷",
+	"冲淡":   "沖淡",
+	"冲走":   "沖走",
+	"冲澡":   "沖澡",
+	"冲厕":   "沖廁",
+	"冲绳":   "沖繩",
+	"冲冲":   "沖沖",
+	"标准":   "標準",
+	"准确":   "準確",
+	"准备":   "準備",
+	"水准":   "水準",
+	"精准":   "精準",
+	"瞄准":   "瞄準",
+	"准时":   "準時",
+	"准则":   "準則",
+	"基准":   "基準",
+	"对准":   "對準",
+	"台风":   "颱風",
+	"柜台":   "櫃檯",
+	"台灯":   "檯燈",
+	"吧台":   "吧檯",
+	"台球":   "檯球",
+	"写字台":  "寫字檯",
+	"一只":   "一隻",
+	"两只":   "兩隻",
+	"几只":   "幾隻",
+	"每只":   "每隻",
+	"茶几":   "茶几",
+	"手表":   "手錶",
+	"钟表":   "鐘錶",
+	"电表":   "電錶",
+	"水表":   "水錶",
+	"怀表":   "懷錶",
+	"卷起":   "捲起",
+	"卷入":   "捲入",
+	"席卷":   "席捲",
+	"卷曲":   "捲曲",
+	"北斗":   "北斗",
+	"漏斗":   "漏斗",
+	"熨斗":   "熨斗",
+	"斗笠":   "斗笠",
+	"烟斗":   "煙斗",
+	"斗篷":   "斗篷",
+	"一斗":   "一斗",
+	"斗胆":   "斗膽",
+	"筋斗":   "筋斗",
+	"星斗":   "星斗",
+	"标签":   "標籤",
+	"书签":   "書籤",
+	"牙签":   "牙籤",
+	"抽签":   "抽籤",
+	"旅游":   "旅遊",
+	"游戏":   "遊戲",
+	"游客":   "遊客",
+	"游览":   "遊覽",
+	"游行":   "遊行",
+	"导游":   "導遊",
+	"郊游":   "郊遊",
+	"游乐":   "遊樂",
+	"游玩":   "遊玩",
+	"周游":   "周遊",
+	"尽管":   "儘管",
+	"尽量":   "儘量",
+	"尽快":   "儘快",
+	"尽早":   "儘早",
+	"稻谷":   "稻穀",
+	"谷物":   "穀物",
+	"五谷":   "五穀",
+	"谷子":   "穀子",
+	"心脏":   "心臟",
+	"肝脏":   "肝臟",
+	"内脏":   "內臟",
+	"肾脏":   "腎臟",
+	"脏器":   "臟器",
+	"五脏":   "五臟",
+	"脾脏":   "脾臟",
+	"计划":   "計劃",
+	"规划":   "規劃",
+	"策划":   "策劃",
+	"划分":   "劃分",
+	"划定":   "劃定",
+	"凶手":   "兇手",
+	"凶猛":   "兇猛",
+	"凶残":   "兇殘",
+	"行凶":   "行兇",
+	"帮凶":   "幫兇",
+	"委托":   "委託",
+	"拜托":   "拜託",
+	"托付":   "託付",
+	"寄托":   "寄託",
+	"推托":   "推託",
+	"宣布":   "宣佈",
+	"公布":   "公佈",
+	"分布":   "分佈",
+	"发布":   "發佈",
+	"布置":   "佈置",
+	"布局":   "佈局",
+	"遍布":   "遍佈",
+	"散布":   "散佈",
+	"颁布":   "頒佈",
+	"密布":   "密佈",
+	"精致":   "精緻",
+	"细致":   "細緻",
+	"别致":   "別緻",
+	"雅致":   "雅緻",
+	"风采":   "風采",
+	"神采":   "神采",
+	"文采":   "文采",
+	"采烈":   "采烈",
+	"打采":   "打采",
+	"小丑":   "小丑",
+	"丑时":   "丑時",
+	"恶心":   "噁心",
+	"收获":   "收穫",
+	"词汇":   "詞彙",
+	"人云":   "人云",
+	"亦云":   "亦云",
+	"云云":   "云云",
+	"忧郁":   "憂鬱",
+	"郁闷":   "鬱悶",
+	"抑郁":   "抑鬱",
+	"郁郁":   "鬱鬱",
+	"阴郁":   "陰鬱",
+	"郁金香":  "鬱金香",
+	"沈阳":   "瀋陽",
+	"老板":   "老闆",
+	"合并":   "合併",
+	"兼并":   "兼併",
+	"吞并":   "吞併",
+	"并购":   "併購",
+	"称赞":   "稱讚",
+	"赞美":   "讚美",
+	"赞扬":   "讚揚",
+	"点赞":   "點讚",
+	"赞叹":   "讚嘆",
+	"夸赞":   "誇讚",
+	"咨询":   "諮詢",
+	"刮风":   "颳風",
+	"秋千":   "鞦韆",
+	"萝卜":   "蘿蔔",
+	"长征":   "長征",
+	"征服":   "征服",
+	"出征":   "出征",
+	"远征":   "遠征",
+	"征战":   "征戰",
+	"征途":   "征途",
+	"宿舍":   "宿舍",
+	"舍友":   "舍友",
+	"校舍":   "校舍",
+	"寒舍":   "寒舍",
+	"农舍":   "農舍",
+	"房舍":   "房舍",
+	"伙伴":   "夥伴",
+	"家伙":   "傢伙",
+	"合伙":   "合夥",
+	"团伙":   "團夥",
+	"伙计":   "夥計",
+	"一伙":   "一夥",
+	"占卜":   "占卜",
+	"占星":   "占星",
+	"周末":   "週末",
+	"一周":   "一週",
+	"上周":   "上週",
+	"下周":   "下週",
+	"本周":   "本週",
+	"这周":   "這週",
+	"每周":   "每週",
+	"两周":   "兩週",
+	"周年":   "週年",
+	"周期":   "週期",
+	"周报":   "週報",
+	"周岁":   "週歲",
+	"周一":   "週一",
+	"周二":   "週二",
+	"周三":   "週三",
+	"周四":   "週四",
+	"周五":   "週五",
+	"周六":   "週六",
+	"周日":   "週日",
+	"杂志":   "雜誌",
+	"标志":   "標誌",
+	"日志":   "日誌",
+	"注册":   "註冊",
+	"注释":   "註釋",
+	"注解":   "註解",
+	"备注":   "備註",
+}
+
+var traditionalToSimplifiedPhrases = map[string]string{
+	"乾隆": "乾隆",
+	"乾坤": "乾坤",
+	"著名": "著名",
+	"著作": "著作",
+	"顯著": "显著",
+	"著稱": "著称",
+	"土著": "土著",
+	"名著": "名著",
+	"論著": "论著",
+	"著述": "著述",
+	"卓著": "卓著",
+	"原著": "原著",
+	"編著": "编著",
+	"巨著": "巨著",
+	"專著": "专著",
+	"著者": "著者",
+	"瞭望": "瞭望",
+	"噁心": "恶心",
+	"諮詢": "咨询",
+	"狼藉": "狼藉",
+	"慰藉": "慰藉",
+	"答覆": "答复",
+	"回覆": "回复",
+	"反覆": "反复",
+}
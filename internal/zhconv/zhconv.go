@@ -0,0 +1,100 @@
+// Package zhconv converts Chinese text between Simplified and Traditional characters with
+// built-in character and phrase tables
+package zhconv
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+type converter struct {
+	chars   map[rune]rune
+	phrases map[string]string
+	// starts holds the first rune of every phrase, to skip phrase lookups elsewhere
+	starts map[rune]bool
+	// maxPhrase is the length of the longest phrase in runes
+	maxPhrase int
+}
+
+var (
+	toTraditional = sync.OnceValue(func() *converter {
+		return newConverter(simplifiedToTraditionalChars, traditionalCharOverrides, simplifiedToTraditionalPhrases)
+	})
+	toSimplified = sync.OnceValue(func() *converter {
+		return newConverter(traditionalToSimplifiedChars, nil, traditionalToSimplifiedPhrases)
+	})
+)
+
+// ToTraditional converts Simplified Chinese characters in text to Traditional ones.
+// Other text is returned unchanged.
+func ToTraditional(text string) string {
+	return toTraditional().convert(text)
+}
+
+// ToSimplified converts Traditional Chinese characters in text to Simplified ones.
+// Other text is returned unchanged.
+func ToSimplified(text string) string {
+	return toSimplified().convert(text)
+}
+
+func newConverter(pairs string, overrides map[rune]rune, phrases map[string]string) *converter {
+	c := &converter{
+		chars:   make(map[rune]rune, utf8.RuneCountInString(pairs)/2+len(overrides)),
+		phrases: phrases,
+		starts:  make(map[rune]bool, len(phrases)),
+	}
+
+	runes := []rune(pairs)
+	for i := 0; i+1 < len(runes); i += 2 {
+		c.chars[runes[i]] = runes[i+1]
+	}
+	for from, to := range overrides {
+		c.chars[from] = to
+	}
+	for phrase := range phrases {
+		first, _ := utf8.DecodeRuneInString(phrase)
+		c.starts[first] = true
+		c.maxPhrase = max(c.maxPhrase, utf8.RuneCountInString(phrase))
+	}
+
+	return c
+}
+
+// convert replaces the longest phrase starting at each position, or the character when
+// no phrase matches
+func (c *converter) convert(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i := 0; i < len(runes); {
+		if c.starts[runes[i]] {
+			if phrase, n := c.matchPhrase(runes[i:]); n > 0 {
+				b.WriteString(phrase)
+				i += n
+				continue
+			}
+		}
+
+		if to, ok := c.chars[runes[i]]; ok {
+			b.WriteRune(to)
+		} else {
+			b.WriteRune(runes[i])
+		}
+		i++
+	}
+
+	return b.String()
+}
+
+// matchPhrase returns the converted longest phrase at the start of runes and its length
+// in runes, or 0 when no phrase matches
+func (c *converter) matchPhrase(runes []rune) (string, int) {
+	for n := min(c.maxPhrase, len(runes)); n > 1; n-- {
+		if phrase, ok := c.phrases[string(runes[:n])]; ok {
+			return phrase, n
+		}
+	}
+	return "", 0
+}
@@ -4,20 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
 	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
 	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
 )
 
 type DashScopeClient struct {
 	baseURL    string
 	httpClient *http.Client
+	timeout    time.Duration
 }
 
 const (
@@ -29,15 +36,24 @@ func NewDashScopeClient(timeout int) *DashScopeClient {
 	return &DashScopeClient{
 		baseURL: ASREndpoint,
 		httpClient: &http.Client{
-			Timeout: time.Duration(timeout) * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
+		timeout: time.Duration(timeout) * time.Second,
 	}
 }
 
+// NeedsUpload reports that DashScope reads audio from OSS uploads
+func (c *DashScopeClient) NeedsUpload() bool {
+	return true
+}
+
 // GetUploadPolicy gets upload policy from DashScope
 func (c *DashScopeClient) GetUploadPolicy(ctx context.Context, apiKey, modelName string) (*models.UploadPolicyData, error) {
 	url := fmt.Sprintf("%s?action=getPolicy&model=%s", UploadBaseURL, modelName)
 
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to create request: %v", err))
@@ -45,28 +61,33 @@ func (c *DashScopeClient) GetUploadPolicy(ctx context.Context, apiKey, modelName
 
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, errors.NewExternalServiceError("DashScope", err.Error())
+		return nil, transportError(errors.NewExternalServiceError("DashScope", err.Error()), err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, errors.NewExternalServiceError("DashScope", fmt.Sprintf("Status: %d, Body: %s", resp.StatusCode, string(body)))
+		return nil, dashScopeError(ctx, "DashScope", resp.StatusCode, body)
 	}
 
 	var uploadResp models.UploadPolicyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&uploadResp); err != nil {
 		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to decode response: %v", err))
 	}
+	requestid.RecordUpstream(ctx, uploadResp.RequestID)
 
 	return &uploadResp.Data, nil
 }
 
 // UploadToOSS uploads file to OSS using the provided policy
 func (c *DashScopeClient) UploadToOSS(ctx context.Context, policy *models.UploadPolicyData, file io.Reader, fileName string) (string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	// Create multipart form
 	var requestBody bytes.Buffer
 	contentType, err := createMultipartForm(&requestBody, policy, file, fileName)
@@ -80,16 +101,20 @@ func (c *DashScopeClient) UploadToOSS(ctx context.Context, policy *models.Upload
 	}
 
 	req.Header.Set("Content-Type", contentType)
+	setRequestID(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", errors.NewUploadError(fmt.Sprintf("Upload failed: %v", err))
+		return "", transportError(errors.NewUploadError(fmt.Sprintf("Upload failed: %v", err)), err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", errors.NewUploadError(fmt.Sprintf("Upload failed with status %d: %s", resp.StatusCode, string(body)))
+		apiErr := errors.NewUploadError(fmt.Sprintf("Upload failed with status %d: %s", resp.StatusCode, string(body)))
+		apiErr.UpstreamCode = strconv.Itoa(resp.StatusCode)
+		apiErr.UpstreamStatus = resp.StatusCode
+		return "", apiErr
 	}
 
 	// Return OSS URL
@@ -140,10 +165,18 @@ func (c *DashScopeClient) CallASR(ctx context.Context, apiKey, audioURL, model s
 		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to marshal ASR request: %v", err))
 	}
 
-	// Log request for debugging
-	// Log request for debugging
-	log.Printf("[DEBUG] ASR Request URL: %s\n", c.baseURL)
-	log.Printf("[DEBUG] ASR Request Body: %s\n", string(jsonData))
+	logger := logging.FromContext(ctx)
+	logger.Debug("ASR request",
+		"url", c.baseURL,
+		"model", model,
+		"audio_url", audioURL,
+		"language", asrRequest.Parameters.ASROptions.Language,
+		"enable_itn", enableITN,
+		"prompt", prompt,
+	)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -153,27 +186,82 @@ func (c *DashScopeClient) CallASR(ctx context.Context, apiKey, audioURL, model s
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-DashScope-OssResourceResolve", "enable")
+	setRequestID(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, errors.NewExternalServiceError("DashScope ASR", err.Error())
+		return nil, transportError(errors.NewExternalServiceError("DashScope ASR", err.Error()), err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		log.Printf("[ERROR] ASR service error - Status: %d, Response: %s\n", resp.StatusCode, string(body))
-		return nil, errors.NewExternalServiceError("DashScope ASR", fmt.Sprintf("Status: %d, Body: %s", resp.StatusCode, string(body)))
+		logger.Error("ASR service error", "status", resp.StatusCode, "response", string(body))
+		return nil, dashScopeError(ctx, "DashScope ASR", resp.StatusCode, body)
 	}
 
 	var asrResponse models.ASRResponse
 	if err := json.NewDecoder(resp.Body).Decode(&asrResponse); err != nil {
 		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to decode ASR response: %v", err))
 	}
+	requestid.RecordUpstream(ctx, asrResponse.Request)
 
 	return &asrResponse, nil
 }
 
+// withTimeout applies the default client timeout unless the caller already set a deadline,
+// so long-running jobs can allow more time than synchronous requests
+func (c *DashScopeClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withDefaultTimeout(ctx, c.timeout)
+}
+
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// dashScopeError builds an external service error from a non-200 DashScope response,
+// keeping the DashScope error code and request ID when the body carries them
+func dashScopeError(ctx context.Context, service string, statusCode int, body []byte) *errors.APIError {
+	apiErr := errors.NewExternalServiceError(service, fmt.Sprintf("Status: %d, Body: %s", statusCode, string(body)))
+	apiErr.UpstreamCode = strconv.Itoa(statusCode)
+	apiErr.UpstreamStatus = statusCode
+
+	var errResp models.DashScopeErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		if errResp.Code != "" {
+			apiErr.UpstreamCode = errResp.Code
+		}
+		apiErr.UpstreamRequestID = errResp.RequestID
+		requestid.RecordUpstream(ctx, errResp.RequestID)
+	}
+
+	return apiErr
+}
+
+// setRequestID forwards our request ID to the upstream service for correlation
+func setRequestID(req *http.Request) {
+	if id := requestid.FromContext(req.Context()); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+}
+
+// transportError tags an error caused by a failed HTTP round trip
+func transportError(apiErr *errors.APIError, err error) *errors.APIError {
+	var netErr net.Error
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		apiErr.UpstreamCode = "canceled"
+	case stderrors.As(err, &netErr) && netErr.Timeout():
+		apiErr.UpstreamCode = "timeout"
+	default:
+		apiErr.UpstreamCode = "network"
+	}
+	return apiErr
+}
+
 // createMultipartForm creates multipart form data for OSS upload using standard library
 func createMultipartForm(writer io.Writer, policy *models.UploadPolicyData, file io.Reader, fileName string) (string, error) {
 	key := fmt.Sprintf("%s/%s", policy.UploadDir, fileName)
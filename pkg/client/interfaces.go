@@ -13,7 +13,28 @@ type Uploader interface {
 	UploadToOSS(ctx context.Context, policy *models.UploadPolicyData, file io.Reader, fileName string) (string, error)
 }
 
+// Backend is an ASR service that models can be routed to. Backends that need the OSS
+// upload step implement ASRProvider and transcribe uploaded audio by URL; the others
+// implement InlineASRProvider and take the audio bytes in the request.
+type Backend interface {
+	NeedsUpload() bool
+}
+
 // ASRProvider defines the interface for ASR operations
 type ASRProvider interface {
 	CallASR(ctx context.Context, apiKey, audioURL, model string, language *models.SupportedLanguage, enableITN bool, prompt string) (*models.ASRResponse, error)
 }
+
+// InlineASRProvider defines the interface for ASR services that take the audio bytes in
+// the request. They authenticate with their own credentials rather than the caller's key.
+type InlineASRProvider interface {
+	TranscribeInline(ctx context.Context, audio io.Reader, fileName, contentType, model string, language *models.SupportedLanguage, prompt string) (*models.ASRResponse, error)
+}
+
+// TaskProvider defines the interface for DashScope asynchronous file transcription tasks
+type TaskProvider interface {
+	SubmitFileTranscription(ctx context.Context, apiKey string, request *models.FileTranscriptionRequest) (*models.TaskResponse, error)
+	GetTask(ctx context.Context, apiKey, taskID string) (*models.TaskResponse, error)
+	WaitTask(ctx context.Context, apiKey, taskID string) (*models.TaskResponse, error)
+	FetchTranscription(ctx context.Context, transcriptionURL string) (*models.FileTranscription, error)
+}
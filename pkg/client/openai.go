@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+)
+
+// OpenAIClient transcribes audio with a server implementing the OpenAI
+// /audio/transcriptions API, such as a self-hosted whisper or vLLM server. The audio is
+// sent inline in the request.
+type OpenAIClient struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// NewOpenAIClient creates a client for the API at baseURL, for example
+// http://localhost:8000/v1. name identifies the server in errors.
+func NewOpenAIClient(name, baseURL, apiKey string, timeout int) *OpenAIClient {
+	return &OpenAIClient{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		timeout: time.Duration(timeout) * time.Second,
+	}
+}
+
+// NeedsUpload reports that the audio is sent inline
+func (c *OpenAIClient) NeedsUpload() bool {
+	return false
+}
+
+// openAITranscription is the verbose_json transcription response. Servers that ignore
+// the requested format and return plain json leave the language and duration empty.
+type openAITranscription struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+type openAIErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    any    `json:"code"`
+	} `json:"error"`
+}
+
+// TranscribeInline posts the audio to the transcriptions endpoint and returns the result
+// as an ASR response
+func (c *OpenAIClient) TranscribeInline(ctx context.Context, audio io.Reader, fileName, contentType, model string, language *models.SupportedLanguage, prompt string) (*models.ASRResponse, error) {
+	service := "ASR provider " + c.name
+	logger := logging.FromContext(ctx)
+	var languageCode string
+	if language != nil {
+		languageCode = string(*language)
+	}
+	logger.Debug("Inline ASR request",
+		"provider", c.name,
+		"url", c.baseURL,
+		"model", model,
+		"language", languageCode,
+		"prompt", prompt,
+	)
+
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Stream the form so that large files are not buffered in memory
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeTranscriptionForm(form, audio, fileName, contentType, model, language, prompt))
+	}()
+	defer func() { _ = body.Close() }()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to create ASR request: %v", err))
+	}
+
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	setRequestID(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, transportError(errors.NewExternalServiceError(service, err.Error()), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		logger.Error("ASR provider error", "provider", c.name, "status", resp.StatusCode, "response", string(respBody))
+		apiErr := errors.NewExternalServiceError(service, fmt.Sprintf("Status: %d, Body: %s", resp.StatusCode, string(respBody)))
+		apiErr.UpstreamCode = strconv.Itoa(resp.StatusCode)
+		apiErr.UpstreamStatus = resp.StatusCode
+		var errResp openAIErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil {
+			if code, ok := errResp.Error.Code.(string); ok && code != "" {
+				apiErr.UpstreamCode = code
+			}
+		}
+		return nil, apiErr
+	}
+
+	var transcription openAITranscription
+	if err := json.NewDecoder(resp.Body).Decode(&transcription); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to decode ASR response: %v", err))
+	}
+	upstreamID := resp.Header.Get("X-Request-ID")
+	requestid.RecordUpstream(ctx, upstreamID)
+
+	return transcription.asrResponse(upstreamID), nil
+}
+
+// asrResponse converts the transcription to the DashScope response shape used by the
+// rest of the server
+func (t *openAITranscription) asrResponse(requestID string) *models.ASRResponse {
+	message := models.ASRMessage{
+		Role:    "assistant",
+		Content: []models.ASRContent{{Text: strings.TrimSpace(t.Text)}},
+	}
+	if models.IsValidLanguage(t.Language) {
+		message.Annotations = []models.ASRAnnotation{{Type: "audio_info", Language: models.SupportedLanguage(t.Language)}}
+	}
+
+	response := &models.ASRResponse{
+		Output:  models.ASROutput{Choices: []models.ASRChoice{{FinishReason: "stop", Message: message}}},
+		Request: requestID,
+	}
+	if t.Duration > 0 {
+		duration := t.Duration
+		response.Usage.Seconds = &duration
+	}
+	return response
+}
+
+// writeTranscriptionForm writes the transcription request form and closes it
+func writeTranscriptionForm(form *multipart.Writer, audio io.Reader, fileName, contentType, model string, language *models.SupportedLanguage, prompt string) error {
+	fields := [][2]string{
+		{"model", model},
+		{"response_format", "verbose_json"},
+	}
+	if language != nil {
+		fields = append(fields, [2]string{"language", string(*language)})
+	}
+	if prompt != "" {
+		fields = append(fields, [2]string{"prompt", prompt})
+	}
+	for _, field := range fields {
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			return fmt.Errorf("failed to write field %s: %w", field[0], err)
+		}
+	}
+
+	partHeader := textproto.MIMEHeader{}
+	partHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, fileName))
+	if contentType != "" {
+		partHeader.Set("Content-Type", contentType)
+	}
+	part, err := form.CreatePart(partHeader)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return form.Close()
+}
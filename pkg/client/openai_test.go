@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"qwen3-compatibility/internal/models"
+)
+
+func TestOpenAIClientTranscribeInline(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     string
+		wantLanguage models.SupportedLanguage
+		wantSeconds  float64
+	}{
+		{
+			name:         "verbose json",
+			response:     `{"text":" hello ","language":"en","duration":3.5,"segments":[]}`,
+			wantLanguage: "en",
+			wantSeconds:  3.5,
+		},
+		{
+			name:     "plain json",
+			response: `{"text":"hello"}`,
+		},
+		{
+			name:        "unsupported language",
+			response:    `{"text":"hello","language":"english","duration":1}`,
+			wantSeconds: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/audio/transcriptions" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer "+testAPIKey {
+					t.Errorf("Authorization = %q", got)
+				}
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("ParseMultipartForm() error = %v", err)
+					return
+				}
+				for field, want := range map[string]string{"model": "whisper", "response_format": "verbose_json", "prompt": "names"} {
+					if got := r.FormValue(field); got != want {
+						t.Errorf("%s = %q, want %q", field, got, want)
+					}
+				}
+				file, header, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("FormFile() error = %v", err)
+					return
+				}
+				data, _ := io.ReadAll(file)
+				if header.Filename != "call.wav" || string(data) != "audio" {
+					t.Errorf("file = %s %q", header.Filename, data)
+				}
+				w.Header().Set("X-Request-ID", "upstream-1")
+				_, _ = io.WriteString(w, tt.response)
+			}))
+			defer server.Close()
+
+			c := NewOpenAIClient("test", server.URL+"/v1/", testAPIKey, 10)
+			resp, err := c.TranscribeInline(context.Background(), strings.NewReader("audio"), "call.wav", "audio/wav", "whisper", nil, "names")
+			if err != nil {
+				t.Fatalf("TranscribeInline() error = %v", err)
+			}
+
+			message := resp.Output.Choices[0].Message
+			if got := message.Content[0].Text; got != "hello" {
+				t.Errorf("text = %q, want %q", got, "hello")
+			}
+			var language models.SupportedLanguage
+			if len(message.Annotations) > 0 {
+				language = message.Annotations[0].Language
+			}
+			if language != tt.wantLanguage {
+				t.Errorf("language = %q, want %q", language, tt.wantLanguage)
+			}
+			var seconds float64
+			if resp.Usage.Seconds != nil {
+				seconds = *resp.Usage.Seconds
+			}
+			if seconds != tt.wantSeconds {
+				t.Errorf("seconds = %v, want %v", seconds, tt.wantSeconds)
+			}
+			if resp.Request != "upstream-1" {
+				t.Errorf("request = %q", resp.Request)
+			}
+		})
+	}
+}
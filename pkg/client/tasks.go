@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/logging"
+	"qwen3-compatibility/internal/models"
+	"qwen3-compatibility/internal/requestid"
+)
+
+// TaskBaseURL is the DashScope API serving the asynchronous task endpoints
+const TaskBaseURL = "https://dashscope.aliyuncs.com/api/v1"
+
+// TaskClient submits and polls DashScope asynchronous file transcription tasks. The base
+// URL can point at any server implementing the task endpoints, such as a local fake.
+type TaskClient struct {
+	baseURL      string
+	httpClient   *http.Client
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+func NewTaskClient(baseURL string, timeout int, pollInterval time.Duration) *TaskClient {
+	return &TaskClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		timeout:      time.Duration(timeout) * time.Second,
+		pollInterval: pollInterval,
+	}
+}
+
+// SubmitFileTranscription starts a file transcription task and returns it as queued
+func (c *TaskClient) SubmitFileTranscription(ctx context.Context, apiKey string, request *models.FileTranscriptionRequest) (*models.TaskResponse, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to marshal transcription task: %v", err))
+	}
+
+	logging.FromContext(ctx).Debug("Transcription task request",
+		"model", request.Model,
+		"file_urls", request.Input.FileURLs,
+		"diarization_enabled", request.Parameters.DiarizationEnabled,
+		"speaker_count", request.Parameters.SpeakerCount,
+	)
+
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/services/audio/asr/transcription", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to create transcription task request: %v", err))
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-DashScope-Async", "enable")
+	req.Header.Set("X-DashScope-OssResourceResolve", "enable")
+	setRequestID(req)
+
+	task, err := c.doTask(req, "DashScope Task")
+	if err != nil {
+		return nil, err
+	}
+	if task.Output.TaskID == "" {
+		return nil, errors.NewExternalServiceError("DashScope Task", "No task ID in response")
+	}
+	return task, nil
+}
+
+// GetTask returns the current status of a task
+func (c *TaskClient) GetTask(ctx context.Context, apiKey, taskID string) (*models.TaskResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/tasks/"+url.PathEscape(taskID), nil)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to create task request: %v", err))
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	setRequestID(req)
+
+	return c.doTask(req, "DashScope Task")
+}
+
+// WaitTask polls a task until it finishes, fails or ctx is done, and returns its last
+// status. Failed tasks are returned without an error; callers check the status.
+func (c *TaskClient) WaitTask(ctx context.Context, apiKey, taskID string) (*models.TaskResponse, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		task, err := c.GetTask(ctx, apiKey, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Output.TaskStatus.IsTerminal() {
+			return task, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, transportError(errors.NewExternalServiceError("DashScope Task", fmt.Sprintf("Task %s did not finish: %v", taskID, ctx.Err())), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// FetchTranscription downloads the transcription of a finished task from the presigned
+// URL of its result
+func (c *TaskClient) FetchTranscription(ctx context.Context, transcriptionURL string) (*models.FileTranscription, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", transcriptionURL, nil)
+	if err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to create transcription request: %v", err))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, transportError(errors.NewExternalServiceError("DashScope Transcription", err.Error()), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, dashScopeError(ctx, "DashScope Transcription", resp.StatusCode, body)
+	}
+
+	var transcription models.FileTranscription
+	if err := json.NewDecoder(resp.Body).Decode(&transcription); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to decode transcription: %v", err))
+	}
+	return &transcription, nil
+}
+
+// doTask sends a task request and decodes the task in the response
+func (c *TaskClient) doTask(req *http.Request, service string) (*models.TaskResponse, error) {
+	ctx := req.Context()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, transportError(errors.NewExternalServiceError(service, err.Error()), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		logging.FromContext(ctx).Error("Task service error", "status", resp.StatusCode, "response", string(body))
+		return nil, dashScopeError(ctx, service, resp.StatusCode, body)
+	}
+
+	var task models.TaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to decode task response: %v", err))
+	}
+	requestid.RecordUpstream(ctx, task.RequestID)
+
+	return &task, nil
+}
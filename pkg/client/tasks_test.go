@@ -0,0 +1,317 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"qwen3-compatibility/internal/errors"
+	"qwen3-compatibility/internal/models"
+)
+
+const testAPIKey = "sk-test"
+
+// fakeTaskServer serves the task endpoints, answering each poll of a task with the next
+// of its statuses and repeating the last one
+type fakeTaskServer struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	statuses map[string][]models.TaskStatus
+	polls    map[string]int
+	// submitted is the last submitted request
+	submitted *models.FileTranscriptionRequest
+	// submitTaskID is returned by submissions, empty to omit the task ID
+	submitTaskID string
+}
+
+func newFakeTaskServer(t *testing.T) (*fakeTaskServer, *httptest.Server) {
+	fake := &fakeTaskServer{
+		t:            t,
+		statuses:     make(map[string][]models.TaskStatus),
+		polls:        make(map[string]int),
+		submitTaskID: "task-1",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /services/audio/asr/transcription", fake.submit)
+	mux.HandleFunc("GET /tasks/{id}", fake.get)
+	mux.HandleFunc("GET /results/{name}", fake.result)
+	server := httptest.NewServer(fake.authorize(mux))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeTaskServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Presigned result URLs carry no authorization header
+		if !strings.HasPrefix(r.URL.Path, "/results/") && r.Header.Get("Authorization") != "Bearer "+testAPIKey {
+			writeJSON(w, http.StatusUnauthorized, models.DashScopeErrorResponse{Code: "InvalidApiKey", Message: "Invalid API-key provided."})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeTaskServer) submit(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-DashScope-Async") != "enable" {
+		f.t.Errorf("X-DashScope-Async = %q, want enable", r.Header.Get("X-DashScope-Async"))
+	}
+	var request models.FileTranscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		f.t.Errorf("decode task request: %v", err)
+	}
+
+	f.mu.Lock()
+	f.submitted = &request
+	taskID := f.submitTaskID
+	f.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, models.TaskResponse{
+		RequestID: "req-submit",
+		Output:    models.TaskOutput{TaskID: taskID, TaskStatus: models.TaskStatusPending},
+	})
+}
+
+func (f *fakeTaskServer) get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	f.mu.Lock()
+	statuses, ok := f.statuses[id]
+	poll := f.polls[id]
+	f.polls[id]++
+	f.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusNotFound, models.DashScopeErrorResponse{RequestID: "req-missing", Code: "InvalidParameter", Message: "task not found"})
+		return
+	}
+
+	output := models.TaskOutput{TaskID: id, TaskStatus: statuses[min(poll, len(statuses)-1)]}
+	switch output.TaskStatus {
+	case models.TaskStatusSucceeded:
+		output.Results = []models.TaskResult{{
+			FileURL:          "oss://audio.wav",
+			TranscriptionURL: "http://" + r.Host + "/results/" + id + ".json",
+			SubtaskStatus:    models.TaskStatusSucceeded,
+		}}
+	case models.TaskStatusFailed:
+		output.Code = "InvalidFile.DownloadFailed"
+		output.Message = "The audio file cannot be downloaded."
+	}
+	writeJSON(w, http.StatusOK, models.TaskResponse{RequestID: "req-" + id, Output: output})
+}
+
+func (f *fakeTaskServer) result(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("name") != "task-1.json" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+		return
+	}
+	writeJSON(w, http.StatusOK, models.FileTranscription{
+		FileURL:    "oss://audio.wav",
+		Properties: models.FileTranscriptionProperties{AudioFormat: "pcm_s16le", OriginalDuration: 2500},
+		Transcripts: []models.FileTranscript{{
+			Text:      "Hello world.",
+			Sentences: []models.TranscriptSentence{{BeginTime: 0, EndTime: 2400, Text: "Hello world."}},
+		}},
+	})
+}
+
+func (f *fakeTaskServer) setStatuses(id string, statuses ...models.TaskStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses[id] = statuses
+}
+
+func (f *fakeTaskServer) pollCount(id string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.polls[id]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestTaskClient(server *httptest.Server) *TaskClient {
+	return NewTaskClient(server.URL+"/", 5, time.Millisecond)
+}
+
+func TestSubmitFileTranscription(t *testing.T) {
+	fake, server := newFakeTaskServer(t)
+	client := newTestTaskClient(server)
+
+	request := &models.FileTranscriptionRequest{
+		Model:      "paraformer-v2",
+		Input:      models.FileTranscriptionInput{FileURLs: []string{"oss://audio.wav"}},
+		Parameters: models.FileTranscriptionParameters{DiarizationEnabled: true, SpeakerCount: 2},
+	}
+	task, err := client.SubmitFileTranscription(context.Background(), testAPIKey, request)
+	if err != nil {
+		t.Fatalf("SubmitFileTranscription() error = %v", err)
+	}
+	if task.Output.TaskID != "task-1" || task.Output.TaskStatus != models.TaskStatusPending {
+		t.Errorf("SubmitFileTranscription() = %+v, want pending task-1", task.Output)
+	}
+	if fake.submitted == nil || fake.submitted.Model != "paraformer-v2" || !fake.submitted.Parameters.DiarizationEnabled {
+		t.Errorf("submitted request = %+v, want %+v", fake.submitted, request)
+	}
+}
+
+func TestSubmitFileTranscriptionErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		apiKey       string
+		submitTaskID string
+		wantUpstream string
+	}{
+		{name: "missing task ID", apiKey: testAPIKey},
+		{name: "unauthorized", apiKey: "sk-wrong", submitTaskID: "task-1", wantUpstream: "InvalidApiKey"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, server := newFakeTaskServer(t)
+			fake.submitTaskID = tt.submitTaskID
+			client := newTestTaskClient(server)
+
+			task, err := client.SubmitFileTranscription(context.Background(), tt.apiKey, &models.FileTranscriptionRequest{Model: "paraformer-v2"})
+			if err == nil {
+				t.Fatalf("SubmitFileTranscription() = %+v, want error", task)
+			}
+			var apiErr *errors.APIError
+			if !stderrors.As(err, &apiErr) || apiErr.Code != http.StatusBadGateway {
+				t.Fatalf("SubmitFileTranscription() error = %v, want external service error", err)
+			}
+			if apiErr.UpstreamCode != tt.wantUpstream {
+				t.Errorf("upstream code = %q, want %q", apiErr.UpstreamCode, tt.wantUpstream)
+			}
+		})
+	}
+}
+
+func TestWaitTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []models.TaskStatus
+		want      models.TaskStatus
+		wantPolls int
+	}{
+		{
+			name:      "succeeded",
+			statuses:  []models.TaskStatus{models.TaskStatusPending, models.TaskStatusRunning, models.TaskStatusRunning, models.TaskStatusSucceeded},
+			want:      models.TaskStatusSucceeded,
+			wantPolls: 4,
+		},
+		{
+			name:      "failed",
+			statuses:  []models.TaskStatus{models.TaskStatusPending, models.TaskStatusRunning, models.TaskStatusFailed},
+			want:      models.TaskStatusFailed,
+			wantPolls: 3,
+		},
+		{
+			name:      "already finished",
+			statuses:  []models.TaskStatus{models.TaskStatusSucceeded},
+			want:      models.TaskStatusSucceeded,
+			wantPolls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, server := newFakeTaskServer(t)
+			fake.setStatuses("task-1", tt.statuses...)
+			client := newTestTaskClient(server)
+
+			task, err := client.WaitTask(context.Background(), testAPIKey, "task-1")
+			if err != nil {
+				t.Fatalf("WaitTask() error = %v", err)
+			}
+			if task.Output.TaskStatus != tt.want {
+				t.Errorf("WaitTask() status = %s, want %s", task.Output.TaskStatus, tt.want)
+			}
+			if polls := fake.pollCount("task-1"); polls != tt.wantPolls {
+				t.Errorf("polls = %d, want %d", polls, tt.wantPolls)
+			}
+			if tt.want == models.TaskStatusFailed && task.Output.Code == "" {
+				t.Errorf("WaitTask() output = %+v, want the failure code", task.Output)
+			}
+		})
+	}
+}
+
+func TestWaitTaskDeadline(t *testing.T) {
+	fake, server := newFakeTaskServer(t)
+	fake.setStatuses("task-1", models.TaskStatusRunning)
+	client := newTestTaskClient(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	task, err := client.WaitTask(ctx, testAPIKey, "task-1")
+	if err == nil {
+		t.Fatalf("WaitTask() = %+v, want error", task.Output)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WaitTask() returned after %v, want at the deadline", elapsed)
+	}
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.UpstreamCode != "timeout" {
+		t.Errorf("WaitTask() error = %v, want a timeout", err)
+	}
+	if fake.pollCount("task-1") < 2 {
+		t.Errorf("polls = %d, want the task polled until the deadline", fake.pollCount("task-1"))
+	}
+}
+
+func TestWaitTaskNotFound(t *testing.T) {
+	_, server := newFakeTaskServer(t)
+	client := newTestTaskClient(server)
+
+	_, err := client.WaitTask(context.Background(), testAPIKey, "missing")
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.UpstreamStatus != http.StatusNotFound || apiErr.UpstreamRequestID != "req-missing" {
+		t.Errorf("WaitTask() error = %v, want the upstream 404", err)
+	}
+}
+
+func TestFetchTranscription(t *testing.T) {
+	fake, server := newFakeTaskServer(t)
+	fake.setStatuses("task-1", models.TaskStatusSucceeded)
+	client := newTestTaskClient(server)
+
+	task, err := client.WaitTask(context.Background(), testAPIKey, "task-1")
+	if err != nil {
+		t.Fatalf("WaitTask() error = %v", err)
+	}
+	transcription, err := client.FetchTranscription(context.Background(), task.Output.Results[0].TranscriptionURL)
+	if err != nil {
+		t.Fatalf("FetchTranscription() error = %v", err)
+	}
+	if transcription.Properties.OriginalDuration != 2500 || len(transcription.Transcripts) != 1 || transcription.Transcripts[0].Text != "Hello world." {
+		t.Errorf("FetchTranscription() = %+v", transcription)
+	}
+}
+
+func TestFetchTranscriptionError(t *testing.T) {
+	_, server := newFakeTaskServer(t)
+	client := newTestTaskClient(server)
+
+	transcription, err := client.FetchTranscription(context.Background(), server.URL+"/results/expired.json")
+	if err == nil {
+		t.Fatalf("FetchTranscription() = %+v, want error", transcription)
+	}
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.UpstreamStatus != http.StatusForbidden {
+		t.Errorf("FetchTranscription() error = %v, want the upstream 403", err)
+	}
+}